@@ -0,0 +1,197 @@
+package curseforge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultBaseURL is the address of the CurseForge Core API
+const DefaultBaseURL = "https://api.curseforge.com"
+
+// MinecraftGameID is the ID of Minecraft on CurseForge, and ModsClassID the ID of its mods category
+const (
+	MinecraftGameID = 432
+	ModsClassID     = 6
+)
+
+// Retry settings used when the client doesn't set its own
+const (
+	DefaultAttempts = 3
+	DefaultTimeout  = 30 * time.Second
+	baseBackoff     = 500 * time.Millisecond
+)
+
+// Client talks to the CurseForge API. Every endpoint it uses only reads data, so requests are
+// retried on network errors, 429 and 5xx. A Client is safe for concurrent use.
+type Client struct {
+	// BaseURL is the API root without a trailing slash, DefaultBaseURL if empty
+	BaseURL   string
+	UserAgent string
+	// APIKey is sent as x-api-key with every request
+	APIKey string
+	// HTTPClient sends the requests, a client with DefaultTimeout is used if nil
+	HTTPClient *http.Client
+	// Attempts is how many times a request is tried, DefaultAttempts if zero
+	Attempts int
+}
+
+var defaultHTTPClient = &http.Client{Timeout: DefaultTimeout}
+
+// NewClient returns a client for the CurseForge API
+func NewClient(userAgent string, apiKey string) *Client {
+	return &Client{BaseURL: DefaultBaseURL, UserAgent: userAgent, APIKey: apiKey}
+}
+
+func (c *Client) baseURL() string {
+	if c.BaseURL == "" {
+		return DefaultBaseURL
+	}
+	return strings.TrimSuffix(c.BaseURL, "/")
+}
+
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient == nil {
+		return defaultHTTPClient
+	}
+	return c.HTTPClient
+}
+
+// get sends a GET request and decodes the data field of the JSON response into out
+func (c *Client) get(ctx context.Context, path string, query url.Values, out any) error {
+	return c.do(ctx, http.MethodGet, path, query, nil, out)
+}
+
+// post sends the JSON encoding of in and decodes the data field of the JSON response into out
+func (c *Client) post(ctx context.Context, path string, in any, out any) error {
+	contents, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	return c.do(ctx, http.MethodPost, path, nil, contents, out)
+}
+
+func (c *Client) do(ctx context.Context, method string, path string, query url.Values, contents []byte, out any) error {
+	requestURL := c.baseURL() + path
+	if len(query) > 0 {
+		requestURL += "?" + query.Encode()
+	}
+
+	attempts := c.Attempts
+	if attempts <= 0 {
+		attempts = DefaultAttempts
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			if err := sleep(ctx, baseBackoff<<(attempt-1)); err != nil {
+				return err
+			}
+		}
+
+		body, retry, err := c.send(ctx, method, requestURL, contents)
+		if err == nil {
+			if out == nil {
+				return nil
+			}
+			// every response is wrapped in {"data": ...}
+			response := struct {
+				Data any `json:"data"`
+			}{out}
+			if err := json.Unmarshal(body, &response); err != nil {
+				return fmt.Errorf("can't decode response of %s %s: %w", method, requestURL, err)
+			}
+			return nil
+		}
+		lastErr = err
+		if !retry || ctx.Err() != nil {
+			break
+		}
+	}
+	return lastErr
+}
+
+// send sends a request once, returns true along with an error if it's worth retrying
+func (c *Client) send(ctx context.Context, method string, requestURL string, contents []byte) ([]byte, bool, error) {
+	var reader io.Reader
+	if contents != nil {
+		reader = bytes.NewReader(contents)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, requestURL, reader)
+	if err != nil {
+		return nil, false, err
+	}
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+	req.Header.Set("x-api-key", c.APIKey)
+	req.Header.Set("Accept", "application/json")
+	if contents != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		// only network failures are worth another try, not errors of the transport itself,
+		// like a cache that has nothing stored for an offline request
+		var urlErr *url.Error
+		var netErr net.Error
+		retry := errors.As(err, &urlErr) && errors.As(urlErr.Err, &netErr)
+		return nil, retry, fmt.Errorf("can't reach CurseForge: %w", err)
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, true, fmt.Errorf("error reading response from CurseForge: %w", err)
+	}
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return body, false, nil
+	}
+
+	apiErr := &APIError{
+		Method:     method,
+		URL:        requestURL,
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Body:       errorBody(body),
+	}
+	retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return nil, retry, apiErr
+}
+
+// errorBody gets the part of an error response worth showing, CurseForge sometimes answers with whole HTML pages
+func errorBody(body []byte) string {
+	text := strings.TrimSpace(string(body))
+	if strings.HasPrefix(text, "<") {
+		return ""
+	}
+	if len(text) > 200 {
+		text = text[:200] + "..."
+	}
+	return text
+}
+
+func sleep(ctx context.Context, duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
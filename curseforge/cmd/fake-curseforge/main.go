@@ -0,0 +1,30 @@
+// Command fake-curseforge serves a fixtures directory as a fake CurseForge API, so gorium can be
+// run against it without the network or an API key:
+//
+//	go run ./cmd/fake-curseforge -fixtures curseforgetest/testdata/basic -addr 127.0.0.1:8081
+//	GORIUM_CURSEFORGE_API=http://127.0.0.1:8081 gorium add cf:jei
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+
+	"gorium/curseforge/curseforgetest"
+)
+
+func main() {
+	fixturesDir := flag.String("fixtures", "curseforgetest/testdata/basic", "directory with the fixtures to serve")
+	addr := flag.String("addr", "127.0.0.1:8081", "address to listen on")
+	apiKey := flag.String("key", "", "API key clients have to send, any key is accepted if empty")
+	flag.Parse()
+
+	fixtures, err := curseforgetest.LoadFixtures(*fixturesDir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fixtures.APIKey = *apiKey
+
+	log.Printf("Serving %d mods and %d files on http://%s", len(fixtures.Mods), len(fixtures.Files), *addr)
+	log.Fatal(http.ListenAndServe(*addr, curseforgetest.NewHandler(fixtures)))
+}
@@ -0,0 +1,131 @@
+package curseforgetest
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+
+	"gorium/curseforge"
+)
+
+// Fixtures is the data a fake server answers with
+type Fixtures struct {
+	Mods  []curseforge.Mod
+	Files []curseforge.File
+	// Contents are the contents of files, keyed by file name. Files listed here get their hashes,
+	// length and fingerprint filled in and a download URL on the fake server.
+	Contents map[string][]byte
+	// APIKey, if set, has to be sent as x-api-key or the server answers 403 like CurseForge does
+	APIKey string
+}
+
+// LoadFixtures reads fixtures from a directory:
+//
+//	mods.json   array of mods
+//	files.json  array of files
+//	files/      contents of files, by file name (optional)
+func LoadFixtures(dir string) (*Fixtures, error) {
+	fixtures := &Fixtures{Contents: map[string][]byte{}}
+
+	sources := []struct {
+		name string
+		into any
+	}{
+		{"mods.json", &fixtures.Mods},
+		{"files.json", &fixtures.Files},
+	}
+	for _, source := range sources {
+		data, err := os.ReadFile(filepath.Join(dir, source.name))
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, source.into); err != nil {
+			return nil, fmt.Errorf("%s: %w", source.name, err)
+		}
+	}
+
+	entries, err := os.ReadDir(filepath.Join(dir, "files"))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, "files", entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		fixtures.Contents[entry.Name()] = data
+	}
+	return fixtures, nil
+}
+
+// fillFiles sets the hashes, length and fingerprint of files that have contents, and the
+// latest file indexes of mods that don't list any
+func (fixtures *Fixtures) fillFiles() {
+	for i := range fixtures.Files {
+		file := &fixtures.Files[i]
+		data, ok := fixtures.Contents[file.FileName]
+		if !ok {
+			continue
+		}
+		sha1Sum := sha1.Sum(data)
+		md5Sum := md5.Sum(data)
+		file.Hashes = []curseforge.FileHash{
+			{Value: hex.EncodeToString(sha1Sum[:]), Algo: curseforge.HashSHA1},
+			{Value: hex.EncodeToString(md5Sum[:]), Algo: curseforge.HashMD5},
+		}
+		file.FileLength = int64(len(data))
+		file.FileFingerprint = curseforge.Fingerprint(data)
+		file.IsAvailable = true
+	}
+
+	for i := range fixtures.Mods {
+		mod := &fixtures.Mods[i]
+		if len(mod.LatestFilesIndexes) > 0 {
+			continue
+		}
+		files := fixtures.modFiles(mod.ID)
+		for _, file := range files {
+			for _, gameVersion := range file.MinecraftVersions() {
+				for _, loader := range file.Loaders() {
+					index := curseforge.FileIndex{
+						GameVersion: gameVersion,
+						FileID:      file.ID,
+						Filename:    file.FileName,
+						ReleaseType: file.ReleaseType,
+						ModLoader:   curseforge.ParseModLoader(loader),
+					}
+					known := slices.ContainsFunc(mod.LatestFilesIndexes, func(existing curseforge.FileIndex) bool {
+						return existing.GameVersion == index.GameVersion && existing.ModLoader == index.ModLoader
+					})
+					if !known {
+						mod.LatestFilesIndexes = append(mod.LatestFilesIndexes, index)
+					}
+				}
+			}
+		}
+	}
+}
+
+// modFiles gets the files of a mod, newest first
+func (fixtures *Fixtures) modFiles(modID int) []curseforge.File {
+	var files []curseforge.File
+	for _, file := range fixtures.Files {
+		if file.ModID == modID {
+			files = append(files, file)
+		}
+	}
+	sort.SliceStable(files, func(i, j int) bool {
+		return files[i].FileDate.After(files[j].FileDate)
+	})
+	return files
+}
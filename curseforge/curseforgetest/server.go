@@ -0,0 +1,338 @@
+// Package curseforgetest provides a fake CurseForge API server driven by fixtures, so code using the
+// curseforge package can be run against a known set of mods without the network or an API key.
+//
+//	fixtures, _ := curseforgetest.LoadFixtures("testdata/basic")
+//	server := curseforgetest.NewServer(fixtures)
+//	defer server.Close()
+//	client := &curseforge.Client{BaseURL: server.URL}
+//
+// Files whose contents are in the fixtures are served under /data/, with Range support.
+package curseforgetest
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gorium/curseforge"
+)
+
+// Server answers the CurseForge endpoints from fixtures
+type Server struct {
+	fixtures *Fixtures
+	mux      *http.ServeMux
+
+	mutex    sync.Mutex
+	requests []string
+}
+
+// NewHandler returns a fake CurseForge API serving the fixtures
+func NewHandler(fixtures *Fixtures) *Server {
+	fixtures.fillFiles()
+	server := &Server{fixtures: fixtures, mux: http.NewServeMux()}
+
+	server.mux.HandleFunc("GET /v1/mods/search", server.search)
+	server.mux.HandleFunc("GET /v1/mods/{modId}", server.getMod)
+	server.mux.HandleFunc("POST /v1/mods", server.getMods)
+	server.mux.HandleFunc("GET /v1/mods/{modId}/files", server.getModFiles)
+	server.mux.HandleFunc("GET /v1/mods/{modId}/files/{fileId}", server.getModFile)
+	server.mux.HandleFunc("GET /v1/mods/{modId}/files/{fileId}/download-url", server.getDownloadURL)
+	server.mux.HandleFunc("POST /v1/mods/files", server.getFiles)
+	server.mux.HandleFunc("POST /v1/fingerprints/{gameId}", server.matchFingerprints)
+	server.mux.HandleFunc("POST /v1/fingerprints", server.matchFingerprints)
+	server.mux.HandleFunc("GET /data/{filename}", server.getFile)
+	return server
+}
+
+// NewServer starts a fake CurseForge API on a local port, its URL can be used as a client's BaseURL
+func NewServer(fixtures *Fixtures) *httptest.Server {
+	return httptest.NewServer(NewHandler(fixtures))
+}
+
+func (server *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	server.mutex.Lock()
+	server.requests = append(server.requests, r.Method+" "+r.URL.Path)
+	server.mutex.Unlock()
+
+	// downloads come from a CDN on the real site, they don't need the key
+	if server.fixtures.APIKey != "" && !strings.HasPrefix(r.URL.Path, "/data/") && r.Header.Get("x-api-key") != server.fixtures.APIKey {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+	server.mux.ServeHTTP(w, r)
+}
+
+// Requests returns the method and path of every request served so far
+func (server *Server) Requests() []string {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	return slices.Clone(server.requests)
+}
+
+// writeData sends a value wrapped in {"data": ...} like every CurseForge response
+func writeData(w http.ResponseWriter, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{"data": value})
+}
+
+// function to read an integer path value, answers 400 if it isn't one
+func pathInt(w http.ResponseWriter, r *http.Request, name string) (int, bool) {
+	value, err := strconv.Atoi(r.PathValue(name))
+	if err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return 0, false
+	}
+	return value, true
+}
+
+func (server *Server) findMod(modID int) *curseforge.Mod {
+	for i, mod := range server.fixtures.Mods {
+		if mod.ID == modID {
+			return &server.fixtures.Mods[i]
+		}
+	}
+	return nil
+}
+
+func (server *Server) findFile(fileID int) *curseforge.File {
+	for i, file := range server.fixtures.Files {
+		if file.ID == fileID {
+			return &server.fixtures.Files[i]
+		}
+	}
+	return nil
+}
+
+// distributable checks if the author of a mod lets other apps download its files
+func (server *Server) distributable(modID int) bool {
+	mod := server.findMod(modID)
+	return mod == nil || mod.AllowModDistribution == nil || *mod.AllowModDistribution
+}
+
+// withURL points a file at this server if its contents are known and the mod may be distributed
+func (server *Server) withURL(r *http.Request, file curseforge.File) curseforge.File {
+	file.DownloadURL = ""
+	if _, ok := server.fixtures.Contents[file.FileName]; ok && server.distributable(file.ModID) {
+		file.DownloadURL = "http://" + r.Host + "/data/" + file.FileName
+	}
+	return file
+}
+
+func (server *Server) withURLs(r *http.Request, files []curseforge.File) []curseforge.File {
+	result := []curseforge.File{}
+	for _, file := range files {
+		result = append(result, server.withURL(r, file))
+	}
+	return result
+}
+
+// function to check if a file is for a game version and loader, empty ones match everything
+func fileMatches(file curseforge.File, gameVersion string, loader curseforge.ModLoader) bool {
+	if gameVersion != "" && !slices.Contains(file.MinecraftVersions(), gameVersion) {
+		return false
+	}
+	return loader == curseforge.ModLoaderAny || slices.Contains(file.Loaders(), loader.String())
+}
+
+func queryLoader(r *http.Request) curseforge.ModLoader {
+	loader, _ := strconv.Atoi(r.URL.Query().Get("modLoaderType"))
+	return curseforge.ModLoader(loader)
+}
+
+func (server *Server) search(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := strings.ToLower(query.Get("searchFilter"))
+	slug := query.Get("slug")
+	gameVersion := query.Get("gameVersion")
+	loader := queryLoader(r)
+
+	mods := []curseforge.Mod{}
+	for _, mod := range server.fixtures.Mods {
+		if slug != "" && mod.Slug != slug {
+			continue
+		}
+		if filter != "" && !strings.Contains(strings.ToLower(mod.Name+" "+mod.Slug+" "+mod.Summary), filter) {
+			continue
+		}
+		if gameVersion != "" || loader != curseforge.ModLoaderAny {
+			matching := slices.ContainsFunc(server.fixtures.modFiles(mod.ID), func(file curseforge.File) bool {
+				return fileMatches(file, gameVersion, loader)
+			})
+			if !matching {
+				continue
+			}
+		}
+		mods = append(mods, mod)
+	}
+
+	index, _ := strconv.Atoi(query.Get("index"))
+	pageSize, err := strconv.Atoi(query.Get("pageSize"))
+	if err != nil || pageSize <= 0 {
+		pageSize = 50
+	}
+	mods = mods[min(index, len(mods)):min(index+pageSize, len(mods))]
+	writeData(w, mods)
+}
+
+func (server *Server) getMod(w http.ResponseWriter, r *http.Request) {
+	modID, ok := pathInt(w, r, "modId")
+	if !ok {
+		return
+	}
+	mod := server.findMod(modID)
+	if mod == nil {
+		http.NotFound(w, r)
+		return
+	}
+	writeData(w, mod)
+}
+
+func (server *Server) getMods(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		ModIDs []int `json:"modIds"`
+	}
+	if json.NewDecoder(r.Body).Decode(&request) != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+	mods := []curseforge.Mod{}
+	for _, modID := range request.ModIDs {
+		if mod := server.findMod(modID); mod != nil {
+			mods = append(mods, *mod)
+		}
+	}
+	writeData(w, mods)
+}
+
+func (server *Server) getModFiles(w http.ResponseWriter, r *http.Request) {
+	modID, ok := pathInt(w, r, "modId")
+	if !ok {
+		return
+	}
+	if server.findMod(modID) == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	query := r.URL.Query()
+	var files []curseforge.File
+	for _, file := range server.fixtures.modFiles(modID) {
+		if fileMatches(file, query.Get("gameVersion"), queryLoader(r)) {
+			files = append(files, file)
+		}
+	}
+
+	index, _ := strconv.Atoi(query.Get("index"))
+	pageSize, err := strconv.Atoi(query.Get("pageSize"))
+	if err != nil || pageSize <= 0 {
+		pageSize = 50
+	}
+	files = files[min(index, len(files)):min(index+pageSize, len(files))]
+	writeData(w, server.withURLs(r, files))
+}
+
+func (server *Server) getModFile(w http.ResponseWriter, r *http.Request) {
+	modID, ok := pathInt(w, r, "modId")
+	if !ok {
+		return
+	}
+	fileID, ok := pathInt(w, r, "fileId")
+	if !ok {
+		return
+	}
+	file := server.findFile(fileID)
+	if file == nil || file.ModID != modID {
+		http.NotFound(w, r)
+		return
+	}
+	writeData(w, server.withURL(r, *file))
+}
+
+func (server *Server) getDownloadURL(w http.ResponseWriter, r *http.Request) {
+	modID, ok := pathInt(w, r, "modId")
+	if !ok {
+		return
+	}
+	fileID, ok := pathInt(w, r, "fileId")
+	if !ok {
+		return
+	}
+	file := server.findFile(fileID)
+	if file == nil || file.ModID != modID {
+		http.NotFound(w, r)
+		return
+	}
+	if !server.distributable(modID) {
+		writeData(w, nil)
+		return
+	}
+	writeData(w, server.withURL(r, *file).DownloadURL)
+}
+
+func (server *Server) getFiles(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		FileIDs []int `json:"fileIds"`
+	}
+	if json.NewDecoder(r.Body).Decode(&request) != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+	var files []curseforge.File
+	for _, fileID := range request.FileIDs {
+		if file := server.findFile(fileID); file != nil {
+			files = append(files, *file)
+		}
+	}
+	writeData(w, server.withURLs(r, files))
+}
+
+func (server *Server) matchFingerprints(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Fingerprints []uint32 `json:"fingerprints"`
+	}
+	if json.NewDecoder(r.Body).Decode(&request) != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	result := curseforge.FingerprintsMatchesResult{
+		IsCacheBuilt:          true,
+		ExactMatches:          []curseforge.FingerprintMatch{},
+		ExactFingerprints:     []uint32{},
+		PartialMatches:        []curseforge.FingerprintMatch{},
+		UnmatchedFingerprints: []uint32{},
+	}
+	for _, fingerprint := range request.Fingerprints {
+		index := slices.IndexFunc(server.fixtures.Files, func(file curseforge.File) bool {
+			return file.FileFingerprint == fingerprint
+		})
+		if index < 0 {
+			result.UnmatchedFingerprints = append(result.UnmatchedFingerprints, fingerprint)
+			continue
+		}
+		file := server.withURL(r, server.fixtures.Files[index])
+		result.ExactMatches = append(result.ExactMatches, curseforge.FingerprintMatch{
+			ID:          file.ModID,
+			File:        file,
+			LatestFiles: server.withURLs(r, server.fixtures.modFiles(file.ModID)),
+		})
+		result.ExactFingerprints = append(result.ExactFingerprints, fingerprint)
+	}
+	writeData(w, result)
+}
+
+func (server *Server) getFile(w http.ResponseWriter, r *http.Request) {
+	data, ok := server.fixtures.Contents[r.PathValue("filename")]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/java-archive")
+	http.ServeContent(w, r, r.PathValue("filename"), time.Time{}, bytes.NewReader(data))
+}
@@ -0,0 +1,9 @@
+// Package curseforge is a client for the parts of the CurseForge Core API (https://docs.curseforge.com/)
+// that a mod manager needs: searching Minecraft mods, listing their files, looking files up by
+// fingerprint and getting download addresses. Every request needs an API key.
+//
+//	client := curseforge.NewClient("owner/app/1.0", apiKey)
+//	mod, err := client.GetMod(context.Background(), 238222)
+//
+// Errors returned for non-2xx responses are *APIError.
+package curseforge
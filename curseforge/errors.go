@@ -0,0 +1,39 @@
+package curseforge
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// APIError is returned when CurseForge answers with a non-2xx status
+type APIError struct {
+	Method     string
+	URL        string
+	StatusCode int
+	Status     string
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	message := fmt.Sprintf("CurseForge returned %s for %s %s", e.Status, e.Method, e.URL)
+	if e.Body != "" {
+		message += ": " + e.Body
+	}
+	return message
+}
+
+func (e *APIError) NotFound() bool {
+	return e.StatusCode == http.StatusNotFound
+}
+
+// Unauthorized reports a missing or invalid API key, CurseForge answers 403 for both
+func (e *APIError) Unauthorized() bool {
+	return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+}
+
+// IsNotFound reports whether err is an APIError for a 404 response
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.NotFound()
+}
@@ -0,0 +1,81 @@
+package curseforge
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// how many files are asked for per page, CurseForge allows up to 50
+const filesPageSize = 50
+
+// FileFilter narrows down the files of a mod, empty fields don't filter
+type FileFilter struct {
+	GameVersion string
+	ModLoader   ModLoader
+}
+
+func (filter *FileFilter) query() url.Values {
+	query := url.Values{}
+	if filter == nil {
+		return query
+	}
+	if filter.GameVersion != "" {
+		query.Set("gameVersion", filter.GameVersion)
+	}
+	if filter.ModLoader != ModLoaderAny {
+		query.Set("modLoaderType", strconv.Itoa(int(filter.ModLoader)))
+	}
+	return query
+}
+
+// GetModFiles lists every file of a mod, going through all pages. filter may be nil.
+func (c *Client) GetModFiles(ctx context.Context, modID int, filter *FileFilter) ([]File, error) {
+	var files []File
+	for {
+		query := filter.query()
+		query.Set("index", strconv.Itoa(len(files)))
+		query.Set("pageSize", strconv.Itoa(filesPageSize))
+
+		var page []File
+		if err := c.get(ctx, "/v1/mods/"+strconv.Itoa(modID)+"/files", query, &page); err != nil {
+			return nil, err
+		}
+		files = append(files, page...)
+		if len(page) < filesPageSize {
+			return files, nil
+		}
+	}
+}
+
+// GetModFile gets a single file of a mod
+func (c *Client) GetModFile(ctx context.Context, modID int, fileID int) (*File, error) {
+	var file File
+	if err := c.get(ctx, "/v1/mods/"+strconv.Itoa(modID)+"/files/"+strconv.Itoa(fileID), nil, &file); err != nil {
+		return nil, err
+	}
+	return &file, nil
+}
+
+// GetFiles gets files by their IDs without knowing their mods, unknown IDs are left out
+func (c *Client) GetFiles(ctx context.Context, fileIDs []int) ([]File, error) {
+	request := struct {
+		FileIDs []int `json:"fileIds"`
+	}{fileIDs}
+
+	var files []File
+	if err := c.post(ctx, "/v1/mods/files", request, &files); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// GetDownloadURL gets the address a file is downloaded from. It's empty for mods whose authors
+// don't allow other apps to download them.
+func (c *Client) GetDownloadURL(ctx context.Context, modID int, fileID int) (string, error) {
+	var downloadURL string
+	if err := c.get(ctx, "/v1/mods/"+strconv.Itoa(modID)+"/files/"+strconv.Itoa(fileID)+"/download-url", nil, &downloadURL); err != nil {
+		return "", err
+	}
+	return downloadURL, nil
+}
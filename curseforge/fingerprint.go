@@ -0,0 +1,76 @@
+package curseforge
+
+import (
+	"io"
+	"os"
+)
+
+// Fingerprint computes CurseForge's fingerprint of a file: MurmurHash2 with seed 1 over the
+// contents without tabs, newlines, carriage returns and spaces
+func Fingerprint(data []byte) uint32 {
+	normalized := make([]byte, 0, len(data))
+	for _, b := range data {
+		if !isWhitespace(b) {
+			normalized = append(normalized, b)
+		}
+	}
+	return murmur2(normalized, 1)
+}
+
+// FingerprintFile computes the fingerprint of a file on disk
+func FingerprintFile(filePath string) (uint32, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return 0, err
+	}
+	defer func(file *os.File) {
+		_ = file.Close()
+	}(file)
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return 0, err
+	}
+	return Fingerprint(data), nil
+}
+
+func isWhitespace(b byte) bool {
+	return b == 9 || b == 10 || b == 13 || b == 32
+}
+
+// murmur2 is the 32-bit MurmurHash2 by Austin Appleby
+func murmur2(data []byte, seed uint32) uint32 {
+	const m = 0x5bd1e995
+	const r = 24
+
+	length := len(data)
+	h := seed ^ uint32(length)
+
+	for len(data) >= 4 {
+		k := uint32(data[0]) | uint32(data[1])<<8 | uint32(data[2])<<16 | uint32(data[3])<<24
+		k *= m
+		k ^= k >> r
+		k *= m
+
+		h *= m
+		h ^= k
+		data = data[4:]
+	}
+
+	switch len(data) {
+	case 3:
+		h ^= uint32(data[2]) << 16
+		fallthrough
+	case 2:
+		h ^= uint32(data[1]) << 8
+		fallthrough
+	case 1:
+		h ^= uint32(data[0])
+		h *= m
+	}
+
+	h ^= h >> 13
+	h *= m
+	h ^= h >> 15
+	return h
+}
@@ -0,0 +1,61 @@
+package curseforge
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+// TestMurmur2Verification runs SMHasher's verification of MurmurHash2: keys 0, 0 1, 0 1 2 and so on
+// are hashed with seed 256 minus their length, and the hashes of all of them are hashed with seed 0
+func TestMurmur2Verification(t *testing.T) {
+	key := make([]byte, 256)
+	hashes := make([]byte, 256*4)
+	for i := 0; i < 256; i++ {
+		key[i] = byte(i)
+		binary.LittleEndian.PutUint32(hashes[i*4:], murmur2(key[:i], uint32(256-i)))
+	}
+	if got := murmur2(hashes, 0); got != 0x27864C1E {
+		t.Fatalf("verification value is %#08x, want 0x27864c1e", got)
+	}
+}
+
+// the values come from a line by line port of the reference C code
+func TestMurmur2(t *testing.T) {
+	tests := []struct {
+		data string
+		seed uint32
+		want uint32
+	}{
+		{"", 0, 0},
+		{"", 1, 0x5bd15e36},
+		{"a", 1, 0x2550b18c},
+		{"abcd", 1, 0xc93f7a16},
+		{"hello world", 1, 0x83ea5dee},
+	}
+	for _, test := range tests {
+		if got := murmur2([]byte(test.data), test.seed); got != test.want {
+			t.Errorf("murmur2(%q, %d) = %#08x, want %#08x", test.data, test.seed, got, test.want)
+		}
+	}
+}
+
+func TestFingerprintIgnoresWhitespace(t *testing.T) {
+	tests := []struct {
+		data string
+		same string
+	}{
+		{"hello world", "helloworld"},
+		{"a\tb\nc\rd e", "abcde"},
+		{" \t\r\n", ""},
+		// only tabs, newlines, carriage returns and spaces are left out
+		{"a\vb\fc", "a\vb\fc"},
+	}
+	for _, test := range tests {
+		if got, want := Fingerprint([]byte(test.data)), murmur2([]byte(test.same), 1); got != want {
+			t.Errorf("Fingerprint(%q) = %d, want %d", test.data, got, want)
+		}
+	}
+	if Fingerprint([]byte("a\vb")) == Fingerprint([]byte("ab")) {
+		t.Errorf("vertical tabs shouldn't be left out")
+	}
+}
@@ -0,0 +1,19 @@
+package curseforge
+
+import (
+	"context"
+	"strconv"
+)
+
+// MatchFingerprints looks files up by their fingerprints, see Fingerprint
+func (c *Client) MatchFingerprints(ctx context.Context, fingerprints []uint32) (*FingerprintsMatchesResult, error) {
+	request := struct {
+		Fingerprints []uint32 `json:"fingerprints"`
+	}{fingerprints}
+
+	var result FingerprintsMatchesResult
+	if err := c.post(ctx, "/v1/fingerprints/"+strconv.Itoa(MinecraftGameID), request, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
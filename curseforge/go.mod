@@ -0,0 +1,3 @@
+module gorium/curseforge
+
+go 1.23.2
@@ -0,0 +1,230 @@
+package curseforge
+
+import (
+	"strings"
+	"time"
+)
+
+// Mod is a project on CurseForge
+type Mod struct {
+	ID                   int         `json:"id"`
+	GameID               int         `json:"gameId"`
+	Name                 string      `json:"name"`
+	Slug                 string      `json:"slug"`
+	Links                Links       `json:"links"`
+	Summary              string      `json:"summary"`
+	Status               int         `json:"status"`
+	DownloadCount        int64       `json:"downloadCount"`
+	IsFeatured           bool        `json:"isFeatured"`
+	PrimaryCategoryID    int         `json:"primaryCategoryId"`
+	Categories           []Category  `json:"categories"`
+	ClassID              int         `json:"classId"`
+	Authors              []Author    `json:"authors"`
+	Logo                 *Asset      `json:"logo"`
+	MainFileID           int         `json:"mainFileId"`
+	LatestFiles          []File      `json:"latestFiles"`
+	LatestFilesIndexes   []FileIndex `json:"latestFilesIndexes"`
+	DateCreated          time.Time   `json:"dateCreated"`
+	DateModified         time.Time   `json:"dateModified"`
+	DateReleased         time.Time   `json:"dateReleased"`
+	AllowModDistribution *bool       `json:"allowModDistribution"`
+	GamePopularityRank   int         `json:"gamePopularityRank"`
+	IsAvailable          bool        `json:"isAvailable"`
+	ThumbsUpCount        int         `json:"thumbsUpCount"`
+}
+
+type Links struct {
+	WebsiteURL string `json:"websiteUrl"`
+	WikiURL    string `json:"wikiUrl"`
+	IssuesURL  string `json:"issuesUrl"`
+	SourceURL  string `json:"sourceUrl"`
+}
+
+type Category struct {
+	ID       int    `json:"id"`
+	GameID   int    `json:"gameId"`
+	Name     string `json:"name"`
+	Slug     string `json:"slug"`
+	URL      string `json:"url"`
+	IconURL  string `json:"iconUrl"`
+	ClassID  int    `json:"classId"`
+	ParentID int    `json:"parentCategoryId"`
+}
+
+type Author struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+type Asset struct {
+	ID           int    `json:"id"`
+	ModID        int    `json:"modId"`
+	Title        string `json:"title"`
+	ThumbnailURL string `json:"thumbnailUrl"`
+	URL          string `json:"url"`
+}
+
+// FileIndex points at the newest file of a mod for a game version and loader
+type FileIndex struct {
+	GameVersion       string      `json:"gameVersion"`
+	FileID            int         `json:"fileId"`
+	Filename          string      `json:"filename"`
+	ReleaseType       ReleaseType `json:"releaseType"`
+	GameVersionTypeID int         `json:"gameVersionTypeId"`
+	ModLoader         ModLoader   `json:"modLoader"`
+}
+
+// File is an uploaded file of a mod, what other sites call a version
+type File struct {
+	ID                   int                   `json:"id"`
+	GameID               int                   `json:"gameId"`
+	ModID                int                   `json:"modId"`
+	IsAvailable          bool                  `json:"isAvailable"`
+	DisplayName          string                `json:"displayName"`
+	FileName             string                `json:"fileName"`
+	ReleaseType          ReleaseType           `json:"releaseType"`
+	FileStatus           int                   `json:"fileStatus"`
+	Hashes               []FileHash            `json:"hashes"`
+	FileDate             time.Time             `json:"fileDate"`
+	FileLength           int64                 `json:"fileLength"`
+	DownloadCount        int64                 `json:"downloadCount"`
+	DownloadURL          string                `json:"downloadUrl"`  // empty if the author doesn't allow other apps to download it
+	GameVersions         []string              `json:"gameVersions"` // game versions, loaders and sides mixed together
+	SortableGameVersions []SortableGameVersion `json:"sortableGameVersions"`
+	Dependencies         []FileDependency      `json:"dependencies"`
+	FileFingerprint      uint32                `json:"fileFingerprint"`
+}
+
+// Hash algorithms of FileHash
+const (
+	HashSHA1 = 1
+	HashMD5  = 2
+)
+
+type FileHash struct {
+	Value string `json:"value"`
+	Algo  int    `json:"algo"`
+}
+
+type SortableGameVersion struct {
+	GameVersionName        string    `json:"gameVersionName"`
+	GameVersionPadded      string    `json:"gameVersionPadded"`
+	GameVersion            string    `json:"gameVersion"`
+	GameVersionReleaseDate time.Time `json:"gameVersionReleaseDate"`
+	GameVersionTypeID      int       `json:"gameVersionTypeId"`
+}
+
+// SHA1 returns the SHA1 hash of the file, empty if CurseForge doesn't have one
+func (file *File) SHA1() string {
+	for _, hash := range file.Hashes {
+		if hash.Algo == HashSHA1 {
+			return hash.Value
+		}
+	}
+	return ""
+}
+
+// Loaders gets the loaders the file is for, like "fabric". CurseForge lists them among the game versions.
+func (file *File) Loaders() []string {
+	var loaders []string
+	for _, name := range file.GameVersions {
+		if loader := ParseModLoader(strings.ToLower(name)); loader != ModLoaderAny {
+			loaders = append(loaders, loader.String())
+		}
+	}
+	return loaders
+}
+
+// MinecraftVersions gets the game versions the file is for, without the loaders and sides listed with them
+func (file *File) MinecraftVersions() []string {
+	var versions []string
+	for _, name := range file.GameVersions {
+		if name != "" && name[0] >= '0' && name[0] <= '9' {
+			versions = append(versions, name)
+		}
+	}
+	return versions
+}
+
+type FileDependency struct {
+	ModID        int          `json:"modId"`
+	RelationType RelationType `json:"relationType"`
+}
+
+type ReleaseType int
+
+const (
+	ReleaseTypeRelease ReleaseType = 1
+	ReleaseTypeBeta    ReleaseType = 2
+	ReleaseTypeAlpha   ReleaseType = 3
+)
+
+type RelationType int
+
+const (
+	RelationEmbeddedLibrary    RelationType = 1
+	RelationOptionalDependency RelationType = 2
+	RelationRequiredDependency RelationType = 3
+	RelationTool               RelationType = 4
+	RelationIncompatible       RelationType = 5
+	RelationInclude            RelationType = 6
+)
+
+type ModLoader int
+
+const (
+	ModLoaderAny        ModLoader = 0
+	ModLoaderForge      ModLoader = 1
+	ModLoaderCauldron   ModLoader = 2
+	ModLoaderLiteLoader ModLoader = 3
+	ModLoaderFabric     ModLoader = 4
+	ModLoaderQuilt      ModLoader = 5
+	ModLoaderNeoForge   ModLoader = 6
+)
+
+// String gets the name other sites use for the loader, like "neoforge", empty for ModLoaderAny
+func (loader ModLoader) String() string {
+	switch loader {
+	case ModLoaderForge:
+		return "forge"
+	case ModLoaderCauldron:
+		return "cauldron"
+	case ModLoaderLiteLoader:
+		return "liteloader"
+	case ModLoaderFabric:
+		return "fabric"
+	case ModLoaderQuilt:
+		return "quilt"
+	case ModLoaderNeoForge:
+		return "neoforge"
+	default:
+		return ""
+	}
+}
+
+// ParseModLoader gets a loader by its name, ModLoaderAny if it's unknown
+func ParseModLoader(name string) ModLoader {
+	for loader := ModLoaderForge; loader <= ModLoaderNeoForge; loader++ {
+		if loader.String() == name {
+			return loader
+		}
+	}
+	return ModLoaderAny
+}
+
+// FingerprintMatch is a file found by its fingerprint
+type FingerprintMatch struct {
+	ID          int    `json:"id"`
+	File        File   `json:"file"`
+	LatestFiles []File `json:"latestFiles"`
+}
+
+type FingerprintsMatchesResult struct {
+	IsCacheBuilt             bool                `json:"isCacheBuilt"`
+	ExactMatches             []FingerprintMatch  `json:"exactMatches"`
+	ExactFingerprints        []uint32            `json:"exactFingerprints"`
+	PartialMatches           []FingerprintMatch  `json:"partialMatches"`
+	PartialMatchFingerprints map[string][]uint32 `json:"partialMatchFingerprints"`
+	UnmatchedFingerprints    []uint32            `json:"unmatchedFingerprints"`
+}
@@ -0,0 +1,102 @@
+package curseforge
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// Sort fields of SearchParams
+const (
+	SortFeatured       = 1
+	SortPopularity     = 2
+	SortLastUpdated    = 3
+	SortName           = 4
+	SortTotalDownloads = 6
+)
+
+// SearchParams are the options of a Minecraft mod search, empty fields don't filter
+type SearchParams struct {
+	SearchFilter string
+	Slug         string
+	GameVersion  string
+	ModLoader    ModLoader
+	SortField    int
+	Index        int
+	PageSize     int
+}
+
+func (params SearchParams) query() url.Values {
+	query := url.Values{
+		"gameId":  {strconv.Itoa(MinecraftGameID)},
+		"classId": {strconv.Itoa(ModsClassID)},
+	}
+	if params.SearchFilter != "" {
+		query.Set("searchFilter", params.SearchFilter)
+	}
+	if params.Slug != "" {
+		query.Set("slug", params.Slug)
+	}
+	if params.GameVersion != "" {
+		query.Set("gameVersion", params.GameVersion)
+	}
+	if params.ModLoader != ModLoaderAny {
+		query.Set("modLoaderType", strconv.Itoa(int(params.ModLoader)))
+	}
+	if params.SortField != 0 {
+		query.Set("sortField", strconv.Itoa(params.SortField))
+		query.Set("sortOrder", "desc")
+	}
+	if params.Index > 0 {
+		query.Set("index", strconv.Itoa(params.Index))
+	}
+	if params.PageSize > 0 {
+		query.Set("pageSize", strconv.Itoa(params.PageSize))
+	}
+	return query
+}
+
+// SearchMods searches Minecraft mods
+func (c *Client) SearchMods(ctx context.Context, params SearchParams) ([]Mod, error) {
+	var mods []Mod
+	if err := c.get(ctx, "/v1/mods/search", params.query(), &mods); err != nil {
+		return nil, err
+	}
+	return mods, nil
+}
+
+// GetMod gets a mod by its ID
+func (c *Client) GetMod(ctx context.Context, modID int) (*Mod, error) {
+	var mod Mod
+	if err := c.get(ctx, "/v1/mods/"+strconv.Itoa(modID), nil, &mod); err != nil {
+		return nil, err
+	}
+	return &mod, nil
+}
+
+// GetModBySlug finds a Minecraft mod by its slug, returns nil if there's none
+func (c *Client) GetModBySlug(ctx context.Context, slug string) (*Mod, error) {
+	mods, err := c.SearchMods(ctx, SearchParams{Slug: slug})
+	if err != nil {
+		return nil, err
+	}
+	for i := range mods {
+		if mods[i].Slug == slug {
+			return &mods[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// GetMods gets several mods at once, unknown IDs are left out of the result
+func (c *Client) GetMods(ctx context.Context, modIDs []int) ([]Mod, error) {
+	request := struct {
+		ModIDs []int `json:"modIds"`
+	}{modIDs}
+
+	var mods []Mod
+	if err := c.post(ctx, "/v1/mods", request, &mods); err != nil {
+		return nil, err
+	}
+	return mods, nil
+}
@@ -0,0 +1,141 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultBaseURL is the address of the GitHub REST API
+const DefaultBaseURL = "https://api.github.com"
+
+// Retry settings used when the client doesn't set its own
+const (
+	DefaultAttempts = 3
+	DefaultTimeout  = 30 * time.Second
+	baseBackoff     = 500 * time.Millisecond
+)
+
+// Client talks to the GitHub API without authentication. Requests are retried on network errors
+// and 5xx, a used up rate limit is returned right away since it only resets once an hour.
+// A Client is safe for concurrent use.
+type Client struct {
+	// BaseURL is the API root without a trailing slash, DefaultBaseURL if empty
+	BaseURL string
+	// UserAgent is sent with every request, GitHub refuses requests without one
+	UserAgent string
+	// HTTPClient sends the requests, a client with DefaultTimeout is used if nil
+	HTTPClient *http.Client
+	// Attempts is how many times a request is tried, DefaultAttempts if zero
+	Attempts int
+}
+
+var defaultHTTPClient = &http.Client{Timeout: DefaultTimeout}
+
+// NewClient returns a client for the public GitHub API
+func NewClient(userAgent string) *Client {
+	return &Client{BaseURL: DefaultBaseURL, UserAgent: userAgent}
+}
+
+func (c *Client) baseURL() string {
+	if c.BaseURL == "" {
+		return DefaultBaseURL
+	}
+	return strings.TrimSuffix(c.BaseURL, "/")
+}
+
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient == nil {
+		return defaultHTTPClient
+	}
+	return c.HTTPClient
+}
+
+// get sends a GET request and decodes the JSON response into out
+func (c *Client) get(ctx context.Context, path string, query url.Values, out any) error {
+	requestURL := c.baseURL() + path
+	if len(query) > 0 {
+		requestURL += "?" + query.Encode()
+	}
+
+	attempts := c.Attempts
+	if attempts <= 0 {
+		attempts = DefaultAttempts
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			if err := sleep(ctx, baseBackoff<<(attempt-1)); err != nil {
+				return err
+			}
+		}
+
+		body, retry, err := c.send(ctx, requestURL)
+		if err == nil {
+			if err := json.Unmarshal(body, out); err != nil {
+				return fmt.Errorf("can't decode response of GET %s: %w", requestURL, err)
+			}
+			return nil
+		}
+		lastErr = err
+		if !retry || ctx.Err() != nil {
+			break
+		}
+	}
+	return lastErr
+}
+
+// send sends a GET request once, returns true along with an error if it's worth retrying
+func (c *Client) send(ctx context.Context, requestURL string) ([]byte, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		// only network failures are worth another try, not errors of the transport itself,
+		// like a cache that has nothing stored for an offline request
+		var urlErr *url.Error
+		var netErr net.Error
+		retry := errors.As(err, &urlErr) && errors.As(urlErr.Err, &netErr)
+		return nil, retry, fmt.Errorf("can't reach GitHub: %w", err)
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, true, fmt.Errorf("error reading response from GitHub: %w", err)
+	}
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return body, false, nil
+	}
+	return nil, resp.StatusCode >= 500, newAPIError(http.MethodGet, requestURL, resp, body)
+}
+
+func sleep(ctx context.Context, duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,28 @@
+// Command fake-github serves a fixtures directory as a fake GitHub API, so gorium can be
+// run against it without the network or GitHub's rate limit:
+//
+//	go run ./cmd/fake-github -fixtures githubtest/testdata/basic -addr 127.0.0.1:8082
+//	GORIUM_GITHUB_API=http://127.0.0.1:8082 gorium add gh:example/tiny-tweaks
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+
+	"gorium/github/githubtest"
+)
+
+func main() {
+	fixturesDir := flag.String("fixtures", "githubtest/testdata/basic", "directory with the fixtures to serve")
+	addr := flag.String("addr", "127.0.0.1:8082", "address to listen on")
+	flag.Parse()
+
+	fixtures, err := githubtest.LoadFixtures(*fixturesDir)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	log.Printf("Serving %d repositories on http://%s", len(fixtures.Repositories), *addr)
+	log.Fatal(http.ListenAndServe(*addr, githubtest.NewHandler(fixtures)))
+}
@@ -0,0 +1,8 @@
+// Package github is a client for the parts of the GitHub REST API (https://docs.github.com/rest)
+// needed to install mods from release assets: repositories and their releases.
+//
+//	client := github.NewClient("owner/app/1.0")
+//	releases, err := client.ListReleases(context.Background(), "owner", "repo")
+//
+// Errors returned for non-2xx responses are *APIError.
+package github
@@ -0,0 +1,60 @@
+package github
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// APIError is returned when GitHub answers with a non-2xx status
+type APIError struct {
+	Method     string
+	URL        string
+	StatusCode int
+	Status     string
+	Message    string // "message" field of GitHub's error body
+	// RateLimitExhausted is set when the request was refused because no requests are left this hour
+	RateLimitExhausted bool
+}
+
+func newAPIError(method string, requestURL string, resp *http.Response, body []byte) *APIError {
+	apiErr := &APIError{
+		Method:     method,
+		URL:        requestURL,
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+	}
+	var errorBody struct {
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(body, &errorBody) == nil {
+		apiErr.Message = errorBody.Message
+	}
+	if resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests {
+		apiErr.RateLimitExhausted = resp.Header.Get("X-RateLimit-Remaining") == "0"
+	}
+	return apiErr
+}
+
+func (e *APIError) Error() string {
+	message := fmt.Sprintf("GitHub returned %s for %s %s", e.Status, e.Method, e.URL)
+	if e.Message != "" {
+		message += ": " + e.Message
+	}
+	return message
+}
+
+func (e *APIError) NotFound() bool {
+	return e.StatusCode == http.StatusNotFound
+}
+
+func (e *APIError) RateLimited() bool {
+	return e.RateLimitExhausted || e.StatusCode == http.StatusTooManyRequests
+}
+
+// IsNotFound reports whether err is an APIError for a 404 response
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.NotFound()
+}
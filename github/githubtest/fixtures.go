@@ -0,0 +1,93 @@
+package githubtest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gorium/github"
+)
+
+// Fixtures is the data a fake server answers with
+type Fixtures struct {
+	Repositories []github.Repository
+	Releases     map[string][]github.Release // keyed by the full name of the repository, like "owner/repo"
+	// Assets are the contents of release assets, keyed by asset name. Assets listed here get
+	// their size and digest filled in and a download URL on the fake server.
+	Assets map[string][]byte
+}
+
+// LoadFixtures reads fixtures from a directory:
+//
+//	repos.json     array of repositories
+//	releases.json  object of repository full name to array of releases
+//	files/         contents of release assets, by asset name (optional)
+func LoadFixtures(dir string) (*Fixtures, error) {
+	fixtures := &Fixtures{Assets: map[string][]byte{}}
+
+	sources := []struct {
+		name string
+		into any
+	}{
+		{"repos.json", &fixtures.Repositories},
+		{"releases.json", &fixtures.Releases},
+	}
+	for _, source := range sources {
+		data, err := os.ReadFile(filepath.Join(dir, source.name))
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, source.into); err != nil {
+			return nil, fmt.Errorf("%s: %w", source.name, err)
+		}
+	}
+
+	entries, err := os.ReadDir(filepath.Join(dir, "files"))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, "files", entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		fixtures.Assets[entry.Name()] = data
+	}
+	return fixtures, nil
+}
+
+// fillAssets sets the size and digest of assets that have contents, dates of assets that have none,
+// and sorts releases newest first
+func (fixtures *Fixtures) fillAssets() {
+	for name, releases := range fixtures.Releases {
+		for i := range releases {
+			for j := range releases[i].Assets {
+				asset := &releases[i].Assets[j]
+				if asset.CreatedAt.IsZero() {
+					asset.CreatedAt = releases[i].PublishedAt
+					asset.UpdatedAt = releases[i].PublishedAt
+				}
+				data, ok := fixtures.Assets[asset.Name]
+				if !ok {
+					continue
+				}
+				sum := sha256.Sum256(data)
+				asset.Size = int64(len(data))
+				asset.Digest = "sha256:" + hex.EncodeToString(sum[:])
+				asset.State = "uploaded"
+			}
+		}
+		sort.SliceStable(releases, func(i, j int) bool {
+			return releases[i].PublishedAt.After(releases[j].PublishedAt)
+		})
+		fixtures.Releases[name] = releases
+	}
+}
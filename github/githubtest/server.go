@@ -0,0 +1,158 @@
+// Package githubtest provides a fake GitHub API server driven by fixtures, so code using the
+// github package can be run against a known set of releases without the network.
+//
+//	fixtures, _ := githubtest.LoadFixtures("testdata/basic")
+//	server := githubtest.NewServer(fixtures)
+//	defer server.Close()
+//	client := &github.Client{BaseURL: server.URL}
+//
+// Assets whose contents are in the fixtures are served like github.com serves them, under
+// /{owner}/{repo}/releases/download/{tag}/{name}, with Range support.
+package githubtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gorium/github"
+)
+
+// Server answers the GitHub endpoints from fixtures
+type Server struct {
+	fixtures *Fixtures
+	mux      *http.ServeMux
+
+	mutex    sync.Mutex
+	requests []string
+}
+
+// NewHandler returns a fake GitHub API serving the fixtures
+func NewHandler(fixtures *Fixtures) *Server {
+	fixtures.fillAssets()
+	server := &Server{fixtures: fixtures, mux: http.NewServeMux()}
+
+	server.mux.HandleFunc("GET /repos/{owner}/{repo}", server.getRepository)
+	server.mux.HandleFunc("GET /repos/{owner}/{repo}/releases", server.listReleases)
+	server.mux.HandleFunc("GET /repos/{owner}/{repo}/releases/tags/{tag}", server.getReleaseByTag)
+	server.mux.HandleFunc("GET /{owner}/{repo}/releases/download/{tag}/{name}", server.getAsset)
+	return server
+}
+
+// NewServer starts a fake GitHub API on a local port, its URL can be used as a client's BaseURL
+func NewServer(fixtures *Fixtures) *httptest.Server {
+	return httptest.NewServer(NewHandler(fixtures))
+}
+
+func (server *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	server.mutex.Lock()
+	server.requests = append(server.requests, r.Method+" "+r.URL.Path)
+	server.mutex.Unlock()
+	server.mux.ServeHTTP(w, r)
+}
+
+// Requests returns the method and path of every request served so far
+func (server *Server) Requests() []string {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	return slices.Clone(server.requests)
+}
+
+func writeJSON(w http.ResponseWriter, value any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+func notFound(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusNotFound)
+	_ = json.NewEncoder(w).Encode(map[string]string{"message": "Not Found"})
+}
+
+// findRepository looks a repository up like GitHub does, ignoring case
+func (server *Server) findRepository(owner string, repo string) *github.Repository {
+	for i, repository := range server.fixtures.Repositories {
+		if strings.EqualFold(repository.FullName, owner+"/"+repo) {
+			return &server.fixtures.Repositories[i]
+		}
+	}
+	return nil
+}
+
+// releases gets the published releases of a repository with their asset URLs pointing at this server
+func (server *Server) releases(r *http.Request, repository *github.Repository) []github.Release {
+	releases := []github.Release{}
+	for _, release := range server.fixtures.Releases[repository.FullName] {
+		if release.Draft {
+			continue
+		}
+		release.Assets = slices.Clone(release.Assets)
+		for i, asset := range release.Assets {
+			if _, ok := server.fixtures.Assets[asset.Name]; ok {
+				release.Assets[i].BrowserDownloadURL = "http://" + r.Host + "/" + repository.FullName + "/releases/download/" + release.TagName + "/" + asset.Name
+			}
+		}
+		releases = append(releases, release)
+	}
+	return releases
+}
+
+func (server *Server) getRepository(w http.ResponseWriter, r *http.Request) {
+	repository := server.findRepository(r.PathValue("owner"), r.PathValue("repo"))
+	if repository == nil {
+		notFound(w)
+		return
+	}
+	writeJSON(w, repository)
+}
+
+func (server *Server) listReleases(w http.ResponseWriter, r *http.Request) {
+	repository := server.findRepository(r.PathValue("owner"), r.PathValue("repo"))
+	if repository == nil {
+		notFound(w)
+		return
+	}
+
+	releases := server.releases(r, repository)
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	perPage, err := strconv.Atoi(r.URL.Query().Get("per_page"))
+	if err != nil || perPage < 1 {
+		perPage = 30
+	}
+	start := min((page-1)*perPage, len(releases))
+	writeJSON(w, releases[start:min(start+perPage, len(releases))])
+}
+
+func (server *Server) getReleaseByTag(w http.ResponseWriter, r *http.Request) {
+	repository := server.findRepository(r.PathValue("owner"), r.PathValue("repo"))
+	if repository == nil {
+		notFound(w)
+		return
+	}
+	for _, release := range server.releases(r, repository) {
+		if release.TagName == r.PathValue("tag") {
+			writeJSON(w, release)
+			return
+		}
+	}
+	notFound(w)
+}
+
+func (server *Server) getAsset(w http.ResponseWriter, r *http.Request) {
+	data, ok := server.fixtures.Assets[r.PathValue("name")]
+	if !ok || server.findRepository(r.PathValue("owner"), r.PathValue("repo")) == nil {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/java-archive")
+	http.ServeContent(w, r, r.PathValue("name"), time.Time{}, bytes.NewReader(data))
+}
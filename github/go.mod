@@ -0,0 +1,3 @@
+module gorium/github
+
+go 1.23.2
@@ -0,0 +1,59 @@
+package github
+
+import "time"
+
+type Repository struct {
+	ID            int64     `json:"id"`
+	Name          string    `json:"name"`
+	FullName      string    `json:"full_name"`
+	Owner         User      `json:"owner"`
+	Description   string    `json:"description"`
+	HTMLURL       string    `json:"html_url"`
+	Homepage      string    `json:"homepage"`
+	Stars         int       `json:"stargazers_count"`
+	License       *License  `json:"license"`
+	Archived      bool      `json:"archived"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	PushedAt      time.Time `json:"pushed_at"`
+	DefaultBranch string    `json:"default_branch"`
+}
+
+type User struct {
+	ID    int64  `json:"id"`
+	Login string `json:"login"`
+}
+
+type License struct {
+	Key    string `json:"key"`
+	Name   string `json:"name"`
+	SPDXID string `json:"spdx_id"`
+}
+
+type Release struct {
+	ID          int64     `json:"id"`
+	TagName     string    `json:"tag_name"`
+	Name        string    `json:"name"`
+	Body        string    `json:"body"`
+	Draft       bool      `json:"draft"`
+	Prerelease  bool      `json:"prerelease"`
+	HTMLURL     string    `json:"html_url"`
+	CreatedAt   time.Time `json:"created_at"`
+	PublishedAt time.Time `json:"published_at"`
+	Author      User      `json:"author"`
+	Assets      []Asset   `json:"assets"`
+}
+
+type Asset struct {
+	ID                 int64     `json:"id"`
+	Name               string    `json:"name"`
+	Label              string    `json:"label"`
+	ContentType        string    `json:"content_type"`
+	State              string    `json:"state"`
+	Size               int64     `json:"size"`
+	DownloadCount      int       `json:"download_count"`
+	BrowserDownloadURL string    `json:"browser_download_url"`
+	Digest             string    `json:"digest,omitempty"` // like "sha256:...", only on assets uploaded since mid 2025
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
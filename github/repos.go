@@ -0,0 +1,48 @@
+package github
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// how many releases are asked for per page, GitHub allows up to 100
+const releasesPageSize = 100
+
+// GetRepository gets a repository by its owner and name
+func (c *Client) GetRepository(ctx context.Context, owner string, repo string) (*Repository, error) {
+	var repository Repository
+	if err := c.get(ctx, "/repos/"+url.PathEscape(owner)+"/"+url.PathEscape(repo), nil, &repository); err != nil {
+		return nil, err
+	}
+	return &repository, nil
+}
+
+// ListReleases lists the releases of a repository newest first, going through all pages.
+// Drafts are only listed for users who can push to the repository.
+func (c *Client) ListReleases(ctx context.Context, owner string, repo string) ([]Release, error) {
+	var releases []Release
+	for page := 1; ; page++ {
+		query := url.Values{
+			"per_page": {strconv.Itoa(releasesPageSize)},
+			"page":     {strconv.Itoa(page)},
+		}
+		var pageReleases []Release
+		if err := c.get(ctx, "/repos/"+url.PathEscape(owner)+"/"+url.PathEscape(repo)+"/releases", query, &pageReleases); err != nil {
+			return nil, err
+		}
+		releases = append(releases, pageReleases...)
+		if len(pageReleases) < releasesPageSize {
+			return releases, nil
+		}
+	}
+}
+
+// GetReleaseByTag gets the release of a tag
+func (c *Client) GetReleaseByTag(ctx context.Context, owner string, repo string, tag string) (*Release, error) {
+	var release Release
+	if err := c.get(ctx, "/repos/"+url.PathEscape(owner)+"/"+url.PathEscape(repo)+"/releases/tags/"+url.PathEscape(tag), nil, &release); err != nil {
+		return nil, err
+	}
+	return &release, nil
+}
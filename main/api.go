@@ -0,0 +1,132 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"time"
+
+	"gorium/curseforge"
+	"gorium/github"
+	"gorium/modrinth"
+)
+
+// environment variable that overrides the Modrinth API address, for mirrors and the fake server
+const modrinthAPIEnv = "GORIUM_MODRINTH_API"
+
+// responseCache keeps Modrinth responses on disk, it also decides if gorium is offline
+var responseCache = getResponseCache()
+
+// modrinthClient is shared by all commands so they also share the rate limit window
+var modrinthClient = newModrinthClient()
+
+func init() {
+	// once a request has failed, the rest of the command doesn't wait for Modrinth again
+	responseCache.OnStale = func(string, error) {
+		goOffline(true)
+	}
+}
+
+func newModrinthClient() *modrinth.Client {
+	client := modrinth.NewClient(FullVersion)
+	client.BaseURL = getModrinthAPI()
+	client.Token = getModrinthToken()
+	client.HTTPClient = &http.Client{
+		Timeout:   modrinth.DefaultTimeout,
+		Transport: responseCache,
+	}
+	client.OnRateLimit = func(wait time.Duration) {
+		fmt.Printf("%sModrinth rate limit reached, waiting %s%s\n", Yellow, wait.Round(time.Second), Reset)
+	}
+	return client
+}
+
+// function to get the Modrinth API address, the environment variable wins over the config
+func getModrinthAPI() string {
+	if api := os.Getenv(modrinthAPIEnv); api != "" {
+		return api
+	}
+	if api := readGlobalSettings().ModrinthAPI; api != "" {
+		return api
+	}
+	return modrinth.DefaultBaseURL
+}
+
+// function to read the settings shared by all profiles, a missing or broken config gives the defaults.
+// A broken config is reported by the command that reads it, not here.
+func readGlobalSettings() MultiConfig {
+	var config MultiConfig
+	configPath, _ := getConfigPath()
+	configFile, err := os.ReadFile(configPath)
+	if err == nil {
+		_ = json.Unmarshal(configFile, &config)
+	}
+	return config
+}
+
+// function to get the folder for cached data, next to the config if the system has no cache folder
+func getCacheDir() string {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		_, configFolder := getConfigPath()
+		return path.Join(configFolder, "cache")
+	}
+	return path.Join(cacheDir, "gorium")
+}
+
+// how long Modrinth responses are used without asking if they changed
+const defaultCacheTTL = 10 * time.Minute
+
+// function to get the cache of Modrinth responses, its TTL can be set with "cachettl" in the config
+func getResponseCache() *modrinth.Cache {
+	ttl := defaultCacheTTL
+	if value := readGlobalSettings().CacheTTL; value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			fmt.Printf("%sInvalid cachettl %q in the config, using %s%s\n", Yellow, value, defaultCacheTTL, Reset)
+		} else {
+			ttl = parsed
+		}
+	}
+	return modrinth.NewCache(path.Join(getCacheDir(), "http"), ttl)
+}
+
+// function to print an error from the API in a readable way
+func printError(err error) {
+	fmt.Printf("%sError: %s%s\n", Red, errorMessage(err), Reset)
+}
+
+// function to describe an error from the API in a readable way
+func errorMessage(err error) string {
+	var apiErr *modrinth.APIError
+	var providerErr *ProviderError
+	var curseforgeErr *curseforge.APIError
+	var githubErr *github.APIError
+	switch {
+	case errors.As(err, &providerErr) && (errors.Is(err, modrinth.ErrOffline) || isNetworkError(err)):
+		return fmt.Sprintf("can't reach %s, check your connection", providerErr.Provider)
+	case errors.As(err, &curseforgeErr) && curseforgeErr.Unauthorized():
+		return `CurseForge didn't accept the API key, check "curseforgekey" in the config`
+	case errors.As(err, &curseforgeErr) && curseforgeErr.StatusCode >= 500:
+		return fmt.Sprintf("CurseForge is having problems (%s), try again later", curseforgeErr.Status)
+	case errors.As(err, &githubErr) && githubErr.RateLimited():
+		return "GitHub rate limit reached, it allows 60 requests an hour, try again later"
+	case errors.As(err, &githubErr) && githubErr.StatusCode >= 500:
+		return fmt.Sprintf("GitHub is having problems (%s), try again later", githubErr.Status)
+	case errors.Is(err, modrinth.ErrOffline):
+		return "Modrinth can't be reached and this isn't cached, try again when online"
+	case isNetworkError(err):
+		return "can't reach Modrinth, check your connection or use --offline"
+	case errors.As(err, &apiErr) && apiErr.Unauthorized():
+		return "Modrinth didn't accept the access token, check it with gorium token"
+	case errors.As(err, &apiErr) && apiErr.RateLimited():
+		return "Modrinth rate limit reached, try again later"
+	case errors.As(err, &apiErr) && apiErr.StatusCode >= 500:
+		return fmt.Sprintf("Modrinth is having problems (%s), try again later", apiErr.Status)
+	default:
+		return err.Error()
+	}
+}
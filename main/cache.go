@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path"
+
+	"gorium/modrinth"
+)
+
+// cacheCommand manages the cache of Modrinth responses and the jar cache
+func cacheCommand(args []string) {
+	if len(args) == 0 {
+		fmt.Println(Red + "Usage: gorium cache <clear/stats/prune>" + Reset)
+		return
+	}
+
+	switch args[0] {
+	case "clear":
+		cache := modrinth.NewCache(path.Join(getCacheDir(), "http"), 0)
+		entries, size, _ := cache.Size()
+		if err := cache.Clear(); err != nil {
+			printError(err)
+			return
+		}
+		fmt.Printf("[%sCleared%s] %d cached responses, %s\n", Green, Reset, entries, formatBytes(size))
+	case "stats":
+		cacheStats()
+	case "prune":
+		pruneJarCache()
+	default:
+		fmt.Println(Red + "Unknown command, use gorium cache <clear/stats/prune>" + Reset)
+	}
+}
+
+// function to print how much space the caches take and how much of the jar cache is in use
+func cacheStats() {
+	jars, err := getCachedJars()
+	if err != nil {
+		printError(err)
+		return
+	}
+	used := getUsedJarHashes()
+
+	var totalSize, unusedSize int64
+	var unused int
+	for _, jar := range jars {
+		totalSize += jar.Size
+		if !used[jar.SHA512] {
+			unused++
+			unusedSize += jar.Size
+		}
+	}
+
+	entries, responsesSize, _ := modrinth.NewCache(path.Join(getCacheDir(), "http"), 0).Size()
+
+	fmt.Printf("Cache folder: %s%s%s\n", White, getCacheDir(), Reset)
+	fmt.Printf("Jars: %d, %s\n", len(jars), formatBytes(totalSize))
+	fmt.Printf("  used by profiles: %d, %s\n", len(jars)-unused, formatBytes(totalSize-unusedSize))
+	fmt.Printf("  unused: %s%d, %s%s\n", Yellow, unused, formatBytes(unusedSize), Reset)
+	fmt.Printf("Cached responses: %d, %s\n", entries, formatBytes(responsesSize))
+	if unused > 0 {
+		fmt.Println("Type gorium cache prune to remove unused jars")
+	}
+}
+
+// function to remove jars from the jar cache that no profile uses
+func pruneJarCache() {
+	jars, err := getCachedJars()
+	if err != nil {
+		printError(err)
+		return
+	}
+	used := getUsedJarHashes()
+
+	var removed int
+	var freed int64
+	for _, jar := range jars {
+		if used[jar.SHA512] {
+			continue
+		}
+		if err := os.Remove(jar.Path); err != nil {
+			printError(err)
+			continue
+		}
+		_ = os.Remove(path.Dir(jar.Path)) // only succeeds once the folder is empty
+		removed++
+		freed += jar.Size
+	}
+	fmt.Printf("[%sPruned%s] %d unused jars, %s freed\n", Green, Reset, removed, formatBytes(freed))
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	"gorium/modrinth"
+)
+
+// Release channels, every channel also allows the more stable ones
+const (
+	ChannelRelease = "release"
+	ChannelBeta    = "beta"
+	ChannelAlpha   = "alpha"
+)
+
+var channels = []string{ChannelRelease, ChannelBeta, ChannelAlpha}
+
+// function to get Modrinth version types allowed by a channel
+func allowedVersionTypes(channel string) []string {
+	switch channel {
+	case ChannelRelease:
+		return []string{"release"}
+	case ChannelBeta:
+		return []string{"release", "beta"}
+	default:
+		return []string{"release", "beta", "alpha"}
+	}
+}
+
+// channelFor returns the channel used for a mod, profiles without a channel allow everything
+func (config Config) channelFor(projectID string) string {
+	if channel, ok := config.ChannelOverrides[projectID]; ok {
+		return channel
+	}
+	if config.Channel == "" {
+		return ChannelAlpha
+	}
+	return config.Channel
+}
+
+// function to drop versions the channel doesn't allow
+func filterByChannel(versions []modrinth.Version, channel string) []modrinth.Version {
+	allowed := allowedVersionTypes(channel)
+	return slices.DeleteFunc(versions, func(version modrinth.Version) bool {
+		return !slices.Contains(allowed, version.VersionType)
+	})
+}
+
+// function to check if a version is newer than the installed one. A narrower channel can make the newest
+// allowed version older than the installed one, and that isn't an update.
+func isNewerVersion(version modrinth.Version, installed modrinth.Version) bool {
+	return version.DatePublished.After(installed.DatePublished)
+}
+
+// channelCommand shows or changes the channel of the active profile or of a single mod.
+// "default" as the channel removes the override of a mod.
+func channelCommand(args []string) {
+	configPath, _ := getConfigPath()
+	configData := readConfig(configPath)
+	if len(configData.Name) == 0 {
+		fmt.Println(Red + "No profile found, type gorium profile create" + Reset)
+		return
+	}
+
+	if len(args) == 0 {
+		fmt.Printf("Channel: %s%s%s\n", Cyan, configData.channelFor(""), Reset)
+		var projectIDs []string
+		for projectID := range configData.ChannelOverrides {
+			projectIDs = append(projectIDs, projectID)
+		}
+		sort.Strings(projectIDs)
+		projects, _ := fetchProjects(projectIDs)
+		for _, projectID := range projectIDs {
+			fmt.Printf("  %s: %s%s%s\n", titleOf(projects, projectID), Cyan, configData.ChannelOverrides[projectID], Reset)
+		}
+		return
+	}
+
+	channel := args[0]
+	if !slices.Contains(channels, channel) && !(channel == "default" && len(args) > 1) {
+		fmt.Println(Red + "Unknown channel, use release, beta or alpha" + Reset)
+		return
+	}
+
+	if len(args) == 1 {
+		configData.Channel = channel
+		saveProfile(configData)
+		fmt.Printf("Channel of %s set to %s%s%s\n", configData.Name, Cyan, channel, Reset)
+		return
+	}
+
+	project := fetchProject(args[1])
+	if project == nil {
+		return
+	}
+	if channel == "default" {
+		delete(configData.ChannelOverrides, project.ID)
+		saveProfile(configData)
+		fmt.Printf("%s now follows the %s%s%s channel\n", project.Title, Cyan, configData.channelFor(project.ID), Reset)
+		return
+	}
+	if configData.ChannelOverrides == nil {
+		configData.ChannelOverrides = map[string]string{}
+	}
+	configData.ChannelOverrides[project.ID] = channel
+	saveProfile(configData)
+	fmt.Printf("Channel of %s set to %s%s%s\n", project.Title, Cyan, channel, Reset)
+}
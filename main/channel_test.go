@@ -0,0 +1,44 @@
+package main
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+// function to get what f prints
+func captureOutput(t *testing.T, f func()) string {
+	t.Helper()
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = writer
+	defer func() {
+		os.Stdout = stdout
+	}()
+	f()
+	_ = writer.Close()
+	output, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(output)
+}
+
+func TestNoVersionsFoundNamesModChannel(t *testing.T) {
+	configData := Config{Channel: ChannelBeta, ChannelOverrides: map[string]string{"AANobbMI": ChannelRelease}}
+
+	if output := captureOutput(t, func() { printNoVersionsFound(configData, "AANobbMI") }); !strings.Contains(output, "release channel") {
+		t.Fatalf("a mod on the release channel got %q", output)
+	}
+	if output := captureOutput(t, func() { printNoVersionsFound(configData, "P7dR8mSH") }); !strings.Contains(output, "beta channel") {
+		t.Fatalf("a mod on the profile's channel got %q", output)
+	}
+	configData.ChannelOverrides["P7dR8mSH"] = ChannelAlpha
+	if output := captureOutput(t, func() { printNoVersionsFound(configData, "P7dR8mSH") }); strings.Contains(output, "channel") {
+		t.Fatalf("a mod that allows everything got %q", output)
+	}
+}
@@ -0,0 +1,263 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"gorium/modrinth"
+	"gorium/modrinth/modrinthtest"
+)
+
+// the fixtures of the fake Modrinth server, fabric-api and modmenu have two releases and a single one,
+// sodium has a release and a newer beta, modmenu needs fabric-api
+const modrinthFixtures = "../modrinth/modrinthtest/testdata/basic"
+
+// newTestProfile points gorium at a fake Modrinth server and a fresh home folder, and makes an active
+// fabric 1.21.1 profile with an empty mods folder, whose path it returns
+func newTestProfile(t *testing.T, channel string) string {
+	t.Helper()
+	fixtures, err := modrinthtest.LoadFixtures(modrinthFixtures)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(modrinthtest.NewHandler(fixtures))
+	t.Cleanup(server.Close)
+
+	home := t.TempDir()
+	for _, env := range []string{"HOME", "USERPROFILE", "XDG_CACHE_HOME", "LocalAppData"} {
+		t.Setenv(env, home)
+	}
+	t.Setenv(modrinthAPIEnv, server.URL)
+	t.Setenv(modrinthTokenEnv, "")
+	t.Setenv(curseforgeAPIEnv, "")
+	t.Setenv(githubAPIEnv, "")
+
+	// the clients are made when the program starts, so they are made again for the new home and server
+	responseCache = getResponseCache()
+	responseCache.OnStale = func(string, error) {
+		goOffline(true)
+	}
+	modrinthClient = newModrinthClient()
+	curseforgeCache = &modrinth.Cache{Dir: responseCache.Dir, TTL: responseCache.TTL}
+	curseforgeClient = newCurseForgeClient()
+	githubCache = &modrinth.Cache{Dir: responseCache.Dir, TTL: responseCache.TTL}
+	githubClient = newGitHubClient()
+
+	configPath, configFolder := getConfigPath()
+	if err := os.MkdirAll(configFolder, 0755); err != nil {
+		t.Fatal(err)
+	}
+	modsPath := filepath.ToSlash(filepath.Join(home, "mods"))
+	if err := os.MkdirAll(modsPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+	config := MultiConfig{Profiles: []Config{{
+		Active:      "*",
+		Name:        "test",
+		ModsFolder:  modsPath,
+		GameVersion: "1.21.1",
+		Loader:      "fabric",
+		Channel:     channel,
+		Hash:        generateRandomHash(),
+	}}}
+	jsonData, _ := json.MarshalIndent(config, "", "  ")
+	if err := os.WriteFile(configPath, jsonData, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return modsPath
+}
+
+// runGorium runs a command the way it's run from the shell
+func runGorium(t *testing.T, args ...string) {
+	t.Helper()
+	oldArgs := os.Args
+	defer func() {
+		os.Args = oldArgs
+	}()
+	os.Args = append([]string{"gorium"}, args...)
+	main()
+}
+
+// function to check that the mods folder holds exactly the given jars
+func expectJars(t *testing.T, modsPath string, jars ...string) {
+	t.Helper()
+	var found []string
+	for _, filename := range getSHA512FilesFromDirectory(modsPath) {
+		found = append(found, filename)
+	}
+	slices.Sort(found)
+	slices.Sort(jars)
+	if !slices.Equal(found, jars) {
+		t.Fatalf("mods folder has %v, want %v", found, jars)
+	}
+}
+
+// function to check the version and install reason of a mod in the lockfile
+func expectLocked(t *testing.T, modsPath string, projectID string, versionID string, reason string) {
+	t.Helper()
+	lock := readLockFile(modsPath)
+	mod := lock.findMod(projectID)
+	if mod == nil {
+		t.Fatalf("%s isn't in the lockfile", projectID)
+	}
+	if mod.VersionID != versionID || mod.Reason != reason {
+		t.Fatalf("%s is locked at %s (%s), want %s (%s)", projectID, mod.VersionID, mod.Reason, versionID, reason)
+	}
+	if hashFileSHA512(path.Join(modsPath, mod.Filename)) != mod.SHA512 {
+		t.Fatalf("%s doesn't match the hash in the lockfile", mod.Filename)
+	}
+}
+
+func TestAddInstallsDependencies(t *testing.T) {
+	modsPath := newTestProfile(t, "")
+
+	runGorium(t, "add", "modmenu")
+
+	expectJars(t, modsPath, "modmenu-11.0.2.jar", "fabric-api-0.104.0+1.21.1.jar")
+	expectLocked(t, modsPath, "mOgUt4GM", "mmenu001", ReasonRequested)
+	expectLocked(t, modsPath, "P7dR8mSH", "fapi0002", ReasonDependency)
+}
+
+func TestAddPinnedVersionOutsideChannel(t *testing.T) {
+	modsPath := newTestProfile(t, ChannelRelease)
+
+	runGorium(t, "add", "sodium@sodm0002")
+
+	expectJars(t, modsPath, "sodium-fabric-0.6.0-beta.2+mc1.21.1.jar")
+	expectLocked(t, modsPath, "AANobbMI", "sodm0002", ReasonRequested)
+}
+
+func TestUpgrade(t *testing.T) {
+	modsPath := newTestProfile(t, "")
+	runGorium(t, "add", "fabric-api@0.102.0+1.21.1")
+	expectJars(t, modsPath, "fabric-api-0.102.0+1.21.1.jar")
+
+	runGorium(t, "upgrade")
+
+	expectJars(t, modsPath, "fabric-api-0.104.0+1.21.1.jar")
+	expectLocked(t, modsPath, "P7dR8mSH", "fapi0002", ReasonRequested)
+}
+
+func TestUpgradeKeepsNewerVersionThanChannel(t *testing.T) {
+	modsPath := newTestProfile(t, ChannelBeta)
+	runGorium(t, "add", "sodium")
+	expectJars(t, modsPath, "sodium-fabric-0.6.0-beta.2+mc1.21.1.jar")
+
+	// the newest release is older than the installed beta
+	runGorium(t, "channel", "release")
+	runGorium(t, "upgrade")
+
+	expectJars(t, modsPath, "sodium-fabric-0.6.0-beta.2+mc1.21.1.jar")
+	expectLocked(t, modsPath, "AANobbMI", "sodm0002", ReasonRequested)
+}
+
+func TestHold(t *testing.T) {
+	modsPath := newTestProfile(t, "")
+	runGorium(t, "add", "fabric-api@fapi0001")
+
+	runGorium(t, "hold", "fabric-api")
+	runGorium(t, "upgrade")
+	expectJars(t, modsPath, "fabric-api-0.102.0+1.21.1.jar")
+
+	runGorium(t, "unhold", "fabric-api")
+	runGorium(t, "upgrade")
+	expectJars(t, modsPath, "fabric-api-0.104.0+1.21.1.jar")
+}
+
+func TestSync(t *testing.T) {
+	modsPath := newTestProfile(t, "")
+	runGorium(t, "add", "modmenu")
+
+	// a missing jar comes back, a jar the lockfile doesn't list is removed, unknown files are left alone
+	if err := os.Remove(path.Join(modsPath, "modmenu-11.0.2.jar")); err != nil {
+		t.Fatal(err)
+	}
+	sodium, err := os.ReadFile(path.Join(modrinthFixtures, "files", "sodium-fabric-0.5.11+mc1.21.jar"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path.Join(modsPath, "sodium.jar"), sodium, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path.Join(modsPath, "custom.jar"), []byte("not a known mod"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	runGorium(t, "sync")
+
+	expectJars(t, modsPath, "modmenu-11.0.2.jar", "fabric-api-0.104.0+1.21.1.jar", "custom.jar")
+	expectLocked(t, modsPath, "mOgUt4GM", "mmenu001", ReasonRequested)
+}
+
+func TestSyncReplacesChangedJar(t *testing.T) {
+	modsPath := newTestProfile(t, "")
+	runGorium(t, "add", "fabric-api")
+
+	jar := path.Join(modsPath, "fabric-api-0.104.0+1.21.1.jar")
+	if err := os.WriteFile(jar, []byte("changed"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	runGorium(t, "sync")
+
+	expectLocked(t, modsPath, "P7dR8mSH", "fapi0002", ReasonRequested)
+}
+
+func TestSyncKeepsOlderJarWhenDownloadFails(t *testing.T) {
+	modsPath := newTestProfile(t, "")
+	runGorium(t, "add", "fabric-api")
+
+	// the folder has the older version, and the locked one can't be downloaded or taken from the jar cache
+	if err := os.RemoveAll(getJarCacheDir()); err != nil {
+		t.Fatal(err)
+	}
+	older, err := os.ReadFile(path.Join(modrinthFixtures, "files", "fabric-api-0.102.0+1.21.1.jar"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path.Join(modsPath, "fabric-api-0.102.0+1.21.1.jar"), older, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(path.Join(modsPath, "fabric-api-0.104.0+1.21.1.jar")); err != nil {
+		t.Fatal(err)
+	}
+	lock := readLockFile(modsPath)
+	lock.findMod("P7dR8mSH").URL = os.Getenv(modrinthAPIEnv) + "/data/missing.jar"
+	writeLockFile(modsPath, lock)
+
+	_, removed, _, failed, err := syncFolder(modsPath, readLockFile(modsPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(removed) != 0 || !slices.Equal(failed, []string{"fabric-api-0.104.0+1.21.1.jar"}) {
+		t.Fatalf("removed %v and failed %v", removed, failed)
+	}
+	expectJars(t, modsPath, "fabric-api-0.102.0+1.21.1.jar")
+}
+
+func TestRemoveWithDependencies(t *testing.T) {
+	modsPath := newTestProfile(t, "")
+	runGorium(t, "add", "modmenu")
+
+	runGorium(t, "remove", "modmenu", "--deps")
+
+	expectJars(t, modsPath)
+	if lock := readLockFile(modsPath); len(lock.Mods) != 0 {
+		t.Fatalf("lockfile still has %v", lock.Mods)
+	}
+}
+
+func TestRemoveKeepsDependenciesByDefault(t *testing.T) {
+	modsPath := newTestProfile(t, "")
+	runGorium(t, "add", "modmenu")
+
+	runGorium(t, "remove", "modmenu")
+
+	expectJars(t, modsPath, "fabric-api-0.104.0+1.21.1.jar")
+	expectLocked(t, modsPath, "P7dR8mSH", "fapi0002", ReasonDependency)
+}
@@ -0,0 +1,274 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+
+	"gorium/modrinth"
+)
+
+// PlannedInstall is a version that is going to be downloaded, along with the reason it was pulled in
+type PlannedInstall struct {
+	Version    *modrinth.Version
+	RequiredBy string // project ID of the mod that needs it, empty if the user asked for it
+}
+
+// function to pick the file that should be installed from a version
+func primaryFile(version *modrinth.Version) modrinth.File {
+	return version.PrimaryFile()
+}
+
+// function to get versions of mods that are already installed, keyed by project ID
+func getInstalledVersions(modsPath string) (map[string]modrinth.Version, error) {
+	installed := map[string]modrinth.Version{}
+	if !dirExists(modsPath) {
+		return installed, nil
+	}
+
+	localFiles := getSHA512FilesFromDirectory(modsPath)
+	if len(localFiles) < 1 {
+		return installed, nil
+	}
+
+	versions, err := fetchVersionsFromFiles(modsPath, localFiles)
+	if err != nil {
+		return nil, err
+	}
+	for _, version := range versions {
+		installed[version.ProjectID] = version
+	}
+	return installed, nil
+}
+
+// function to get project information for several projects at once, keyed by project ID.
+// Every provider is asked for its own projects.
+func fetchProjects(projectIDs []string) (map[string]modrinth.Project, error) {
+	projects := map[string]modrinth.Project{}
+	if len(projectIDs) == 0 {
+		return projects, nil
+	}
+
+	byProvider := map[string][]string{}
+	for _, projectID := range projectIDs {
+		name := providerFor(projectID).Name()
+		byProvider[name] = append(byProvider[name], projectID)
+	}
+
+	for _, provider := range providers {
+		ids := byProvider[provider.Name()]
+		if len(ids) == 0 {
+			continue
+		}
+		projectList, err := provider.Projects(ids)
+		// the other providers are optional, their mods get their cached projects if they fail
+		if errors.Is(err, modrinth.ErrOffline) || isNetworkError(err) || (err != nil && !isDefaultProvider(provider)) {
+			for id, project := range cachedProjects(ids) {
+				projects[id] = project
+			}
+			continue
+		}
+		if err != nil {
+			return projects, err
+		}
+		rememberProjects(projectList...)
+
+		for _, project := range projectList {
+			projects[project.ID] = project
+		}
+	}
+	return projects, nil
+}
+
+// function to get the title of a project, falls back to its ID if the project is unknown
+func titleOf(projects map[string]modrinth.Project, projectID string) string {
+	if project, ok := projects[projectID]; ok {
+		return project.Title
+	}
+	return projectID
+}
+
+// function to get a project by its slug or ID, errors are printed
+func fetchProject(slugOrID string) *modrinth.Project {
+	provider, name, err := splitProvider(slugOrID)
+	if err != nil {
+		printError(err)
+		return nil
+	}
+	project, err := provider.Project(name)
+	if errors.Is(err, modrinth.ErrOffline) || isNetworkError(err) {
+		if cached := cachedProject(name); cached != nil {
+			return cached
+		}
+	}
+	if err != nil {
+		printModError(slugOrID, err)
+		return nil
+	}
+	rememberProjects(*project)
+	return project
+}
+
+// function to get a single version by its ID
+func fetchVersion(versionID string) (*modrinth.Version, error) {
+	return providerFor(versionID).Version(versionID)
+}
+
+// resolveDependencies walks the required dependencies of the requested versions and returns
+// everything that has to be downloaded. Projects from installed are not pulled in again.
+func resolveDependencies(requested []*modrinth.Version, installed map[string]modrinth.Version, configData Config, backward []bool) ([]PlannedInstall, error) {
+	var plan []PlannedInstall
+	planned := map[string]bool{}
+
+	for _, version := range requested {
+		if planned[version.ProjectID] {
+			continue
+		}
+		planned[version.ProjectID] = true
+		plan = append(plan, PlannedInstall{Version: version})
+	}
+
+	// plan grows while we walk it, so every new version gets its dependencies checked as well
+	for i := 0; i < len(plan); i++ {
+		parent := plan[i].Version
+		for _, dependency := range parent.Dependencies {
+			if dependency.DependencyType != "required" {
+				continue
+			}
+
+			if _, ok := installed[dependency.ProjectID]; ok || planned[dependency.ProjectID] {
+				continue
+			}
+
+			var version *modrinth.Version
+			if dependency.VersionID != "" {
+				var err error
+				version, err = fetchVersion(dependency.VersionID)
+				if err != nil {
+					return nil, err
+				}
+			} else {
+				version = fetchLatestVersion(dependency.ProjectID, configData, backward)
+				if version == nil {
+					return nil, fmt.Errorf("no compatible version of %s found, it is required by %s", dependency.ProjectID, parent.ProjectID)
+				}
+			}
+
+			// version pins may come without a project ID, so check again once we know it
+			if _, ok := installed[version.ProjectID]; ok || planned[version.ProjectID] {
+				continue
+			}
+			planned[version.ProjectID] = true
+			plan = append(plan, PlannedInstall{Version: version, RequiredBy: parent.ProjectID})
+		}
+	}
+	return plan, nil
+}
+
+// function to print what is going to be installed and why
+func printInstallPlan(plan []PlannedInstall) {
+	var projectIDs []string
+	for _, install := range plan {
+		projectIDs = append(projectIDs, install.Version.ProjectID)
+	}
+	// titles are only cosmetic, project IDs are shown if they can't be fetched
+	projects, _ := fetchProjects(projectIDs)
+
+	fmt.Printf("%sThe following mods will be installed:%s\n", Bold, Reset)
+	for _, install := range plan {
+		reason := "requested"
+		if install.RequiredBy != "" {
+			reason = "required by " + titleOf(projects, install.RequiredBy)
+		}
+		fmt.Printf("  %s %s%s%s (%s) - %s\n", titleOf(projects, install.Version.ProjectID), Yellow, install.Version.VersionNumber, Reset, primaryFile(install.Version).Filename, reason)
+	}
+}
+
+// installVersions resolves dependencies of the requested versions and downloads all of them
+func installVersions(requested []*modrinth.Version, configData Config, backward []bool, force bool) {
+	modsPath := configData.ModsFolder
+
+	installed, err := getInstalledVersions(modsPath)
+	if err != nil {
+		printError(err)
+		return
+	}
+
+	plan, err := resolveDependencies(requested, installed, configData, backward)
+	if err != nil {
+		printError(err)
+		return
+	}
+
+	if !force {
+		var incoming []*modrinth.Version
+		for _, install := range plan {
+			incoming = append(incoming, install.Version)
+			delete(installed, install.Version.ProjectID)
+		}
+		conflicts := findIncompatibilities(incoming, installed)
+		if len(conflicts) > 0 {
+			printIncompatibilities(conflicts)
+			return
+		}
+	}
+
+	printInstallPlan(plan)
+
+	for _, install := range plan {
+		if err := resolveDownloadURL(install.Version); err != nil {
+			printError(err)
+			return
+		}
+	}
+
+	var filesToDownload []map[string]string
+	for _, install := range plan {
+		file := primaryFile(install.Version)
+		filesToDownload = append(filesToDownload, map[string]string{
+			"url":      file.URL,
+			"filename": file.Filename,
+			"sha1":     file.Hashes.SHA1,
+			"sha512":   file.Hashes.SHA512,
+			"sha256":   file.Hashes.SHA256,
+		})
+	}
+	downloadErr := downloadFilesConcurrently(modsPath, filesToDownload)
+
+	lock := readLockFile(modsPath)
+	for _, install := range plan {
+		file := primaryFile(install.Version)
+		filePath := path.Join(modsPath, file.Filename)
+		if !dirExists(filePath) || (file.Hashes.SHA512 != "" && hashFileSHA512(filePath) != file.Hashes.SHA512) {
+			continue // download failed, the error has already been reported
+		}
+		fillDownloadedHash(install.Version, modsPath)
+		file = primaryFile(install.Version)
+		rememberVersionFiles(install.Version)
+
+		reason, requiredBy := ReasonRequested, ""
+		if install.RequiredBy != "" {
+			reason, requiredBy = ReasonDependency, install.RequiredBy
+		}
+
+		if existing := lock.findMod(install.Version.ProjectID); existing != nil {
+			if existing.Reason == ReasonRequested {
+				reason, requiredBy = ReasonRequested, ""
+			}
+			// the old jar of the same project would otherwise be loaded alongside the new one
+			if existing.Filename != file.Filename && dirExists(path.Join(modsPath, existing.Filename)) {
+				err := os.Remove(path.Join(modsPath, existing.Filename))
+				checkError(err)
+			}
+		}
+
+		lock.setMod(lockedModFromVersion(install.Version, file, reason, requiredBy))
+	}
+	writeLockFile(modsPath, lock)
+
+	if downloadErr != nil {
+		fmt.Printf("%sSome mods couldn't be downloaded, run the command again to retry%s\n", Red, Reset)
+		os.Exit(1)
+	}
+}
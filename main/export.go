@@ -0,0 +1,209 @@
+package main
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// exportCommand writes the active profile as a modpack, mrpack is the only format for now
+func exportCommand(args []string) {
+	if len(args) == 0 || args[0] != "mrpack" {
+		fmt.Println(Red + "Usage: gorium export mrpack [--output <file>] [--loader-version <version>] [--version <pack version>] [--no-overrides]" + Reset)
+		return
+	}
+
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	output := flags.String("output", "", "file to write, the profile name with .mrpack if empty")
+	loaderVersion := flags.String("loader-version", "", "version of the loader, remembered in the profile")
+	packVersion := flags.String("version", "1.0.0", "version of the modpack")
+	noOverrides := flags.Bool("no-overrides", false, "leave out files that can't be downloaded from Modrinth or GitHub")
+	parseFlags(flags, args[1:])
+
+	configPath, _ := getConfigPath()
+	configData := readConfig(configPath)
+	if len(configData.Name) == 0 {
+		fmt.Println(Red + "No profile found, type gorium profile create" + Reset)
+		return
+	}
+
+	if _, ok := mrpackLoaders[configData.Loader]; !ok {
+		fmt.Printf("%sModpacks can't use the %s loader%s\n", Red, configData.Loader, Reset)
+		return
+	}
+	if *loaderVersion != "" && *loaderVersion != configData.LoaderVersion {
+		configData.LoaderVersion = *loaderVersion
+		saveProfile(configData)
+	}
+	if configData.LoaderVersion == "" {
+		fmt.Printf("%sThe modpack needs the version of %s, give it with --loader-version%s\n", Red, configData.Loader, Reset)
+		return
+	}
+
+	if *output == "" {
+		*output = configData.Name + ".mrpack"
+	}
+
+	index, overrides, err := buildMrpackIndex(configData, *packVersion)
+	if err != nil {
+		printError(err)
+		return
+	}
+	if *noOverrides {
+		for _, filename := range overrides {
+			fmt.Printf("[%sSkipped%s] %s, it can't be downloaded from Modrinth or GitHub\n", Yellow, Reset, filename)
+		}
+		overrides = nil
+	}
+
+	if err := writeMrpack(*output, index, configData.ModsFolder, overrides); err != nil {
+		printError(err)
+		return
+	}
+	for _, filename := range overrides {
+		fmt.Printf("[%sOverride%s] %s\n", Cyan, Reset, filename)
+	}
+	fmt.Printf("[%sExported%s] %s, %d mods to download and %d in overrides\n", Green, Reset, *output, len(index.Files), len(overrides))
+}
+
+// buildMrpackIndex lists the mods of a profile in a modpack index. Files a launcher can download are put
+// in the index, the file names of the others are returned for overrides/.
+func buildMrpackIndex(configData Config, packVersion string) (MrpackIndex, []string, error) {
+	index := MrpackIndex{
+		FormatVersion: mrpackFormatVersion,
+		Game:          "minecraft",
+		VersionID:     packVersion,
+		Name:          configData.Name,
+		Files:         []MrpackFile{},
+		Dependencies: map[string]string{
+			"minecraft":                      configData.GameVersion,
+			mrpackLoaders[configData.Loader]: configData.LoaderVersion,
+		},
+	}
+
+	modsPath := configData.ModsFolder
+	installed, err := getInstalledMods(modsPath)
+	if err != nil {
+		return index, nil, err
+	}
+	lock := readLockFile(modsPath)
+
+	inIndex := map[string]bool{}
+	addFile := func(filename string, downloadURL string, env *MrpackEnv) {
+		filePath := path.Join(modsPath, filename)
+		info, err := os.Stat(filePath)
+		if err != nil {
+			return
+		}
+		index.Files = append(index.Files, MrpackFile{
+			Path:      "mods/" + filename,
+			Hashes:    hashFileSums(filePath),
+			Env:       env,
+			Downloads: []string{downloadURL},
+			FileSize:  info.Size(),
+		})
+		inIndex[filename] = true
+	}
+
+	for _, mod := range installed {
+		file := fileWithHash(&mod.Version, mod.Hash)
+		provider := providerFor(mod.Version.ProjectID)
+		// files from a mirror or the fake server can't be in the index, launchers won't download them
+		if !mrpackDownloadAllowed(file.URL) {
+			continue
+		}
+		env := &MrpackEnv{Client: "required", Server: "required"}
+		if isDefaultProvider(provider) {
+			env = &MrpackEnv{Client: mrpackSide(mod.Project.ClientSide), Server: mrpackSide(mod.Project.ServerSide)}
+		}
+		addFile(mod.Filename, file.URL, env)
+	}
+	for _, mod := range lock.Unmanaged {
+		if !inIndex[mod.Filename] && mrpackDownloadAllowed(mod.Origin) {
+			addFile(mod.Filename, mod.Origin, &MrpackEnv{Client: "required", Server: "required"})
+		}
+	}
+	sort.Slice(index.Files, func(i, j int) bool {
+		return index.Files[i].Path < index.Files[j].Path
+	})
+
+	var overrides []string
+	for _, filename := range getSHA512FilesFromDirectory(modsPath) {
+		if !inIndex[filename] {
+			overrides = append(overrides, filename)
+		}
+	}
+	sort.Strings(overrides)
+	return index, overrides, nil
+}
+
+// function to write a modpack, it's written to a temporary file first so a failed export leaves nothing behind
+func writeMrpack(output string, index MrpackIndex, modsPath string, overrides []string) error {
+	temp, err := os.CreateTemp(filepath.Dir(output), "."+filepath.Base(output)+".*")
+	if err != nil {
+		return err
+	}
+	defer func(name string) {
+		_ = os.Remove(name)
+	}(temp.Name())
+
+	archive := zip.NewWriter(temp)
+	err = writeMrpackContents(archive, index, modsPath, overrides)
+	if closeErr := archive.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := temp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(temp.Name(), 0644)
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(temp.Name(), output)
+}
+
+func writeMrpackContents(archive *zip.Writer, index MrpackIndex, modsPath string, overrides []string) error {
+	indexWriter, err := createMrpackEntry(archive, mrpackIndexName)
+	if err != nil {
+		return err
+	}
+	jsonData, _ := json.MarshalIndent(index, "", "  ")
+	if _, err := indexWriter.Write(jsonData); err != nil {
+		return err
+	}
+
+	for _, filename := range overrides {
+		if strings.ContainsAny(filename, `/\`) {
+			return errors.New("unexpected file name " + filename)
+		}
+		writer, err := createMrpackEntry(archive, mrpackOverrides+"/mods/"+filename)
+		if err != nil {
+			return err
+		}
+		file, err := os.Open(path.Join(modsPath, filename))
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(writer, file)
+		_ = file.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func createMrpackEntry(archive *zip.Writer, name string) (io.Writer, error) {
+	return archive.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: time.Now()})
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"archive/zip"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestMrpackDownloadAllowed(t *testing.T) {
+	tests := []struct {
+		address string
+		want    bool
+	}{
+		{"https://cdn.modrinth.com/data/AANobbMI/versions/sodm0002/sodium.jar", true},
+		{"https://github.com/example/tiny-tweaks/releases/download/v2.0.0/tiny-tweaks.jar", true},
+		{"https://CDN.Modrinth.com/data/x.jar", true},
+		{"http://cdn.modrinth.com/data/x.jar", false},
+		{"http://127.0.0.1:8080/data/sodium.jar", false},
+		{"https://mirror.example.com/data/sodium.jar", false},
+		{"https://cdn.modrinth.com.example.com/x.jar", false},
+		{"", false},
+	}
+	for _, test := range tests {
+		if got := mrpackDownloadAllowed(test.address); got != test.want {
+			t.Errorf("mrpackDownloadAllowed(%q) = %v, want %v", test.address, got, test.want)
+		}
+	}
+}
+
+func TestExportMirroredFilesGoToOverrides(t *testing.T) {
+	newTestProfile(t, "")
+	runGorium(t, "add", "modmenu")
+
+	// the fake server hands the files out from a local address, like a mirror would
+	output := filepath.Join(t.TempDir(), "pack.mrpack")
+	runGorium(t, "export", "mrpack", "--output", output, "--loader-version", "0.16.5")
+
+	archive, err := zip.OpenReader(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(archive *zip.ReadCloser) {
+		_ = archive.Close()
+	}(archive)
+	index, err := readMrpackIndex(&archive.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(index.Files) != 0 {
+		t.Fatalf("the index lists %v", index.Files)
+	}
+
+	var names []string
+	for _, file := range archive.File {
+		names = append(names, file.Name)
+	}
+	slices.Sort(names)
+	want := []string{mrpackIndexName, "overrides/mods/fabric-api-0.104.0+1.21.1.jar", "overrides/mods/modmenu-11.0.2.jar"}
+	if !slices.Equal(names, want) {
+		t.Fatalf("%s has %v, want %v", output, names, want)
+	}
+}
@@ -2,7 +2,12 @@ module gorium/main
 
 go 1.23.2
 
-require gorium/cli v0.0.0-00010101000000-000000000000
+require (
+	gorium/cli v0.0.0-00010101000000-000000000000
+	gorium/curseforge v0.0.0-00010101000000-000000000000
+	gorium/github v0.0.0-00010101000000-000000000000
+	gorium/modrinth v0.0.0-00010101000000-000000000000
+)
 
 require (
 	golang.org/x/sys v0.25.0
@@ -11,3 +16,9 @@ require (
 )
 
 replace gorium/cli => ../cli
+
+replace gorium/curseforge => ../curseforge
+
+replace gorium/github => ../github
+
+replace gorium/modrinth => ../modrinth
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+
+	"gorium/cli"
+	"gorium/modrinth"
+)
+
+func (config Config) isHeld(projectID string) bool {
+	return slices.Contains(config.Held, projectID)
+}
+
+// holdCommand holds or releases a mod in the active profile, without a mod name a menu is shown
+func holdCommand(args []string, hold bool) {
+	configPath, _ := getConfigPath()
+	configData := readConfig(configPath)
+	if len(configData.Name) == 0 {
+		fmt.Println(Red + "No profile found, type gorium profile create" + Reset)
+		return
+	}
+
+	var projectID string
+	if len(args) > 0 {
+		project := fetchProject(args[0])
+		if project == nil {
+			return
+		}
+		projectID = project.ID
+	} else if hold {
+		installed, err := getInstalledMods(configData.ModsFolder)
+		if err != nil {
+			printError(err)
+			return
+		}
+		for id := range installed {
+			if configData.isHeld(id) {
+				delete(installed, id)
+			}
+		}
+		if len(installed) == 0 {
+			fmt.Println("No mods to hold")
+			return
+		}
+		projectID = chooseInstalledMod(installed, "Select the mod you want to hold")
+	} else {
+		if len(configData.Held) == 0 {
+			fmt.Println("No held mods")
+			return
+		}
+		projects, err := fetchProjects(configData.Held)
+		if err != nil {
+			printError(err)
+			return
+		}
+		menu := cli.NewMenu("Select the mod you want to unhold")
+		for _, id := range configData.Held {
+			menu.AddItem(titleOf(projects, id), id)
+		}
+		projectID = menu.Display()
+	}
+	if projectID == "" {
+		return
+	}
+
+	projects, _ := fetchProjects([]string{projectID})
+	title := titleOf(projects, projectID)
+
+	if hold {
+		if configData.isHeld(projectID) {
+			fmt.Printf("%s is already held\n", title)
+			return
+		}
+		configData.Held = append(configData.Held, projectID)
+		saveProfile(configData)
+		fmt.Printf("[%sHeld%s] %s\n", Yellow, Reset, title)
+		return
+	}
+
+	if !configData.isHeld(projectID) {
+		fmt.Printf("%s is not held\n", title)
+		return
+	}
+	configData.Held = slices.DeleteFunc(configData.Held, func(id string) bool {
+		return id == projectID
+	})
+	saveProfile(configData)
+	fmt.Printf("[%sReleased%s] %s\n", Green, Reset, title)
+}
+
+// function to tell which held mods could be upgraded
+func printHeldUpdates(current map[string]modrinth.Version, heldUpdates map[string]modrinth.Version) {
+	var projectIDs []string
+	for hash, update := range heldUpdates {
+		if isNewerVersion(update, current[hash]) {
+			projectIDs = append(projectIDs, update.ProjectID)
+		}
+	}
+	if len(projectIDs) == 0 {
+		return
+	}
+	projects, _ := fetchProjects(projectIDs)
+
+	for hash, update := range heldUpdates {
+		if !isNewerVersion(update, current[hash]) {
+			continue
+		}
+		fmt.Printf("[%sHeld%s] %s %s -> %s%s%s is available\n", Yellow, Reset, titleOf(projects, update.ProjectID), current[hash].VersionNumber, Green, update.VersionNumber, Reset)
+	}
+}
@@ -0,0 +1,339 @@
+package main
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// importCommand installs a modpack into an instance folder and makes a profile for its mods folder,
+// which becomes the active one like a profile made with gorium profile create
+func importCommand(args []string) {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	dir := flags.String("dir", "", "instance folder to install the modpack into")
+	side := flags.String("side", "client", "client or server, decides which files and overrides are installed")
+	name := flags.String("name", "", "name of the new profile, the modpack's name if empty")
+	positional := parseFlags(flags, args)
+	if len(positional) < 1 || *dir == "" {
+		fmt.Println(Red + "Usage: gorium import <pack.mrpack> --dir <instance folder> [--side client/server] [--name <profile name>]" + Reset)
+		return
+	}
+	if *side != "client" && *side != "server" {
+		fmt.Println(Red + "--side has to be client or server" + Reset)
+		return
+	}
+
+	instance, err := filepath.Abs(*dir)
+	checkError(err)
+	modsPath := path.Join(filepath.ToSlash(instance), "mods")
+	configPath, _ := getConfigPath()
+	for _, profile := range readFullConfig(configPath).Profiles {
+		if path.Clean(profile.ModsFolder) == modsPath {
+			fmt.Printf("%sProfile %s already uses %s, switch to it with gorium profile switch%s\n", Red, profile.Name, modsPath, Reset)
+			return
+		}
+	}
+
+	archive, err := zip.OpenReader(positional[0])
+	if err != nil {
+		printError(err)
+		return
+	}
+	defer func(archive *zip.ReadCloser) {
+		_ = archive.Close()
+	}(archive)
+
+	index, err := readMrpackIndex(&archive.Reader)
+	if err != nil {
+		printError(err)
+		return
+	}
+	newConfig, err := mrpackProfile(index)
+	if err != nil {
+		printError(err)
+		return
+	}
+	newConfig.ModsFolder = modsPath
+	newConfig.Name = index.Name
+	if *name != "" {
+		newConfig.Name = *name
+	}
+	newConfig.Hash = generateRandomHash()
+
+	fmt.Printf("%sInstalling %s %s for %s %s %s into %s%s\n", Bold, index.Name, index.VersionID, newConfig.Loader, newConfig.LoaderVersion, newConfig.GameVersion, instance, Reset)
+
+	failed := downloadMrpackFiles(instance, index, *side)
+	if len(failed) > 0 {
+		for _, filePath := range failed {
+			fmt.Printf("[%sFailed%s] %s\n", Red, Reset, filePath)
+		}
+		fmt.Printf("%s%d files couldn't be installed, run the import again to retry%s\n", Red, len(failed), Reset)
+		os.Exit(1)
+	}
+
+	extracted, err := extractMrpackOverrides(&archive.Reader, instance, *side)
+	if err != nil {
+		printError(err)
+		os.Exit(1)
+	}
+
+	// the new profile is made active first, so only its own sources are asked about the mods
+	addProfile(newConfig)
+	lockMrpackMods(modsPath, index)
+	fmt.Printf("[%sImported%s] %s as profile %s, %d files downloaded and %d from overrides\n", Green, Reset, index.Name, newConfig.Name, len(mrpackSideFiles(index, *side)), extracted)
+	fmt.Printf("%s is now the active profile, go back with gorium profile switch\n", newConfig.Name)
+}
+
+// function to read and check the modrinth.index.json of a modpack
+func readMrpackIndex(archive *zip.Reader) (MrpackIndex, error) {
+	var index MrpackIndex
+	file, err := archive.Open(mrpackIndexName)
+	if err != nil {
+		return index, fmt.Errorf("this isn't a Modrinth modpack, it has no %s", mrpackIndexName)
+	}
+	defer func(file io.ReadCloser) {
+		_ = file.Close()
+	}(file)
+
+	if err := json.NewDecoder(file).Decode(&index); err != nil {
+		return index, fmt.Errorf("can't read %s: %w", mrpackIndexName, err)
+	}
+	if index.FormatVersion != mrpackFormatVersion || index.Game != "minecraft" {
+		return index, fmt.Errorf("modpacks of format %d for %q aren't supported", index.FormatVersion, index.Game)
+	}
+	for _, file := range index.Files {
+		if !isInstancePath(file.Path) {
+			return index, fmt.Errorf("the modpack wants to put a file at %s, which is outside the instance", file.Path)
+		}
+	}
+	// overrides are checked before anything is downloaded, so a bad modpack leaves nothing behind
+	for _, file := range archive.File {
+		for _, prefix := range mrpackOverridePrefixes() {
+			if relative, found := strings.CutPrefix(file.Name, prefix); found && relative != "" && !isInstancePath(relative) {
+				return index, fmt.Errorf("the modpack has an override at %s, which is outside the instance", file.Name)
+			}
+		}
+	}
+	return index, nil
+}
+
+// function to check if a path from a modpack stays inside the instance folder
+func isInstancePath(name string) bool {
+	return filepath.IsLocal(filepath.FromSlash(name)) && !strings.Contains(name, `\`)
+}
+
+func mrpackOverridePrefixes() []string {
+	return []string{mrpackOverrides + "/", "client-" + mrpackOverrides + "/", "server-" + mrpackOverrides + "/"}
+}
+
+// function to make a profile out of the game version and loader a modpack depends on
+func mrpackProfile(index MrpackIndex) (Config, error) {
+	var config Config
+	config.GameVersion = index.Dependencies["minecraft"]
+	if config.GameVersion == "" {
+		return config, errors.New("the modpack doesn't say which Minecraft version it's for")
+	}
+	for _, loader := range mrpackLoaderPriority {
+		if version, ok := index.Dependencies[mrpackLoaders[loader]]; ok {
+			config.Loader = loader
+			config.LoaderVersion = version
+			break
+		}
+	}
+	if config.Loader == "" {
+		return config, errors.New("the modpack doesn't use a loader gorium knows")
+	}
+	return config, nil
+}
+
+// function to get the files of a modpack that are used on a side
+func mrpackSideFiles(index MrpackIndex, side string) []MrpackFile {
+	var files []MrpackFile
+	for _, file := range index.Files {
+		if file.Env != nil {
+			env := file.Env.Client
+			if side == "server" {
+				env = file.Env.Server
+			}
+			if env == "unsupported" {
+				continue
+			}
+		}
+		files = append(files, file)
+	}
+	return files
+}
+
+// downloadMrpackFiles downloads the files of a modpack into the instance, checking them against their hashes.
+// A file that fails is tried from the next of its addresses. It returns the paths of the files that failed.
+func downloadMrpackFiles(instance string, index MrpackIndex, side string) []string {
+	var failed []string
+	pending := mrpackSideFiles(index, side)
+	for attempt := 0; len(pending) > 0; attempt++ {
+		// files are downloaded one folder at a time, since a download goes into a single folder
+		folders := map[string][]MrpackFile{}
+		for _, file := range pending {
+			if attempt >= len(file.Downloads) {
+				failed = append(failed, file.Path)
+				continue
+			}
+			folder := path.Join(filepath.ToSlash(instance), path.Dir(file.Path))
+			folders[folder] = append(folders[folder], file)
+		}
+
+		var names []string
+		for folder := range folders {
+			names = append(names, folder)
+		}
+		sort.Strings(names)
+		pending = nil
+		for _, folder := range names {
+			if err := os.MkdirAll(folder, 0755); err != nil {
+				printError(err)
+				return []string{folder}
+			}
+			var downloads []map[string]string
+			for _, file := range folders[folder] {
+				downloads = append(downloads, map[string]string{
+					"url":      file.Downloads[attempt],
+					"filename": path.Base(file.Path),
+					"sha1":     file.Hashes.SHA1,
+					"sha512":   file.Hashes.SHA512,
+				})
+			}
+			if err := downloadFilesConcurrently(folder, downloads); err == nil {
+				continue
+			}
+			for _, file := range folders[folder] {
+				if !mrpackFileDownloaded(instance, file) {
+					pending = append(pending, file)
+				}
+			}
+		}
+	}
+	return failed
+}
+
+// function to check if a file of a modpack is in the instance with the right contents
+func mrpackFileDownloaded(instance string, file MrpackFile) bool {
+	filePath := path.Join(filepath.ToSlash(instance), file.Path)
+	if !dirExists(filePath) {
+		return false
+	}
+	hashes := hashFileSums(filePath)
+	return (file.Hashes.SHA512 == "" || hashes.SHA512 == file.Hashes.SHA512) && (file.Hashes.SHA1 == "" || hashes.SHA1 == file.Hashes.SHA1)
+}
+
+// extractMrpackOverrides copies overrides/ and then client-overrides/ or server-overrides/ into the instance,
+// so files for the side win. Entries that would end up outside the instance stop the import.
+func extractMrpackOverrides(archive *zip.Reader, instance string, side string) (int, error) {
+	extracted := 0
+	for _, prefix := range []string{mrpackOverrides + "/", side + "-" + mrpackOverrides + "/"} {
+		for _, file := range archive.File {
+			relative, found := strings.CutPrefix(file.Name, prefix)
+			if !found || relative == "" {
+				continue
+			}
+			if !isInstancePath(relative) {
+				return extracted, fmt.Errorf("the modpack has an override at %s, which is outside the instance", file.Name)
+			}
+			destination := filepath.Join(instance, filepath.FromSlash(relative))
+			if file.FileInfo().IsDir() {
+				if err := os.MkdirAll(destination, 0755); err != nil {
+					return extracted, err
+				}
+				continue
+			}
+			if !file.Mode().IsRegular() {
+				return extracted, fmt.Errorf("the modpack has an override at %s that isn't a regular file", file.Name)
+			}
+			if err := extractZipFile(file, destination); err != nil {
+				return extracted, err
+			}
+			extracted++
+		}
+	}
+	return extracted, nil
+}
+
+func extractZipFile(file *zip.File, destination string) error {
+	if err := os.MkdirAll(filepath.Dir(destination), 0755); err != nil {
+		return err
+	}
+	in, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer func(in io.ReadCloser) {
+		_ = in.Close()
+	}(in)
+
+	out, err := os.OpenFile(destination, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(out, in)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// lockMrpackMods writes the lockfile of an imported mods folder, so gorium can upgrade and sync it.
+// Downloaded jars no provider knows become unmanaged mods, jars from overrides are left as they are.
+func lockMrpackMods(modsPath string, index MrpackIndex) {
+	localFiles := getSHA512FilesFromDirectory(modsPath)
+	if len(localFiles) == 0 {
+		return
+	}
+	versions, err := fetchVersionsFromFiles(modsPath, localFiles)
+	if err != nil {
+		fmt.Printf("%sCan't look up the mods of the modpack, they are left out of %s%s\n", Yellow, LockFileName, Reset)
+		return
+	}
+
+	// mods other mods of the pack need are recorded as dependencies, so remove --deps can clean them up
+	var hashes []string
+	for hash := range versions {
+		hashes = append(hashes, hash)
+	}
+	sort.Strings(hashes)
+	requiredBy := map[string]string{}
+	for _, hash := range hashes {
+		version := versions[hash]
+		for _, dependency := range version.Dependencies {
+			if dependency.DependencyType == "required" && dependency.ProjectID != version.ProjectID && requiredBy[dependency.ProjectID] == "" {
+				requiredBy[dependency.ProjectID] = version.ProjectID
+			}
+		}
+	}
+
+	lock := readLockFile(modsPath)
+	for hash, version := range versions {
+		file := fileWithHash(&version, hash)
+		file.Hashes.SHA512 = hash
+		reason := ReasonRequested
+		if requiredBy[version.ProjectID] != "" {
+			reason = ReasonDependency
+		}
+		lock.setMod(lockedModFromVersion(&version, file, reason, requiredBy[version.ProjectID]))
+	}
+	for _, file := range index.Files {
+		if path.Dir(file.Path) != "mods" || len(file.Downloads) == 0 {
+			continue
+		}
+		if _, known := versions[file.Hashes.SHA512]; known || localFiles[file.Hashes.SHA512] == "" {
+			continue
+		}
+		lock.setUnmanaged(UnmanagedMod{Filename: path.Base(file.Path), SHA512: file.Hashes.SHA512, Origin: file.Downloads[0]})
+	}
+	writeLockFile(modsPath, lock)
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+)
+
+// writeTestMrpack writes a modpack with files of the fake Modrinth server, the first address of every
+// file is one the server doesn't have
+func writeTestMrpack(t *testing.T, filenames ...string) string {
+	t.Helper()
+	index := MrpackIndex{
+		FormatVersion: mrpackFormatVersion,
+		Game:          "minecraft",
+		VersionID:     "1.0.0",
+		Name:          "Test pack",
+		Dependencies:  map[string]string{"minecraft": "1.21.1", "fabric-loader": "0.16.5"},
+	}
+	api := os.Getenv(modrinthAPIEnv)
+	for _, filename := range filenames {
+		fixture := path.Join(modrinthFixtures, "files", filename)
+		info, err := os.Stat(fixture)
+		if err != nil {
+			t.Fatal(err)
+		}
+		index.Files = append(index.Files, MrpackFile{
+			Path:      "mods/" + filename,
+			Hashes:    hashFileSums(fixture),
+			Downloads: []string{api + "/data/missing-" + filename, api + "/data/" + filename},
+			FileSize:  info.Size(),
+		})
+	}
+
+	output := filepath.Join(t.TempDir(), "pack.mrpack")
+	if err := writeMrpack(output, index, "", nil); err != nil {
+		t.Fatal(err)
+	}
+	return output
+}
+
+func TestImportTriesEveryAddress(t *testing.T) {
+	newTestProfile(t, "")
+	pack := writeTestMrpack(t, "modmenu-11.0.2.jar", "fabric-api-0.104.0+1.21.1.jar")
+	instance := t.TempDir()
+
+	runGorium(t, "import", pack, "--dir", instance)
+
+	modsPath := filepath.ToSlash(filepath.Join(instance, "mods"))
+	if profile := activeProfile(); profile.ModsFolder != modsPath {
+		t.Fatalf("the active profile uses %s, want %s", profile.ModsFolder, modsPath)
+	}
+	expectJars(t, modsPath, "modmenu-11.0.2.jar", "fabric-api-0.104.0+1.21.1.jar")
+}
+
+func TestImportLocksDependencies(t *testing.T) {
+	newTestProfile(t, "")
+	pack := writeTestMrpack(t, "modmenu-11.0.2.jar", "fabric-api-0.104.0+1.21.1.jar")
+	instance := t.TempDir()
+	runGorium(t, "import", pack, "--dir", instance)
+
+	modsPath := filepath.ToSlash(filepath.Join(instance, "mods"))
+	expectLocked(t, modsPath, "mOgUt4GM", "mmenu001", ReasonRequested)
+	expectLocked(t, modsPath, "P7dR8mSH", "fapi0002", ReasonDependency)
+
+	runGorium(t, "remove", "modmenu", "--deps")
+	expectJars(t, modsPath)
+}
+
+func TestMrpackProfilePicksLoader(t *testing.T) {
+	tests := []struct {
+		dependencies map[string]string
+		loader       string
+		version      string
+	}{
+		{map[string]string{"minecraft": "1.21.1", "fabric-loader": "0.16.5"}, "fabric", "0.16.5"},
+		{map[string]string{"minecraft": "1.21.1", "fabric-loader": "0.16.5", "quilt-loader": "0.26.4"}, "quilt", "0.26.4"},
+		{map[string]string{"minecraft": "1.21.1", "forge": "52.0.1", "neoforge": "21.1.66"}, "neoforge", "21.1.66"},
+		{map[string]string{"minecraft": "1.21.1"}, "", ""},
+	}
+	for _, test := range tests {
+		// maps are iterated in a random order, so every index is read a few times
+		for range 10 {
+			config, err := mrpackProfile(MrpackIndex{Dependencies: test.dependencies})
+			if test.loader == "" {
+				if err == nil {
+					t.Fatalf("%v gave the %s loader", test.dependencies, config.Loader)
+				}
+				break
+			}
+			if err != nil || config.Loader != test.loader || config.LoaderVersion != test.version {
+				t.Fatalf("%v gave %s %s (%v), want %s %s", test.dependencies, config.Loader, config.LoaderVersion, err, test.loader, test.version)
+			}
+		}
+	}
+}
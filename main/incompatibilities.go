@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+
+	"gorium/modrinth"
+)
+
+// Incompatibility describes a pair of mods that must not be installed together
+type Incompatibility struct {
+	Version     *modrinth.Version // version that declares the incompatibility
+	Conflicting *modrinth.Version // version it conflicts with
+	Installed   bool              // whether the conflicting version is already installed
+}
+
+// function to check if a dependency entry points to the given version
+func dependencyMatches(dependency modrinth.Dependency, version *modrinth.Version) bool {
+	if dependency.VersionID != "" {
+		return dependency.VersionID == version.ID
+	}
+	return dependency.ProjectID == version.ProjectID
+}
+
+// findIncompatibilities checks the incoming versions against each other and against installed ones,
+// in both directions, since either side may be the one declaring the incompatibility
+func findIncompatibilities(incoming []*modrinth.Version, installed map[string]modrinth.Version) []Incompatibility {
+	var conflicts []Incompatibility
+
+	check := func(version *modrinth.Version, other *modrinth.Version, otherInstalled bool) {
+		for _, dependency := range version.Dependencies {
+			if dependency.DependencyType == "incompatible" && dependencyMatches(dependency, other) {
+				conflicts = append(conflicts, Incompatibility{Version: version, Conflicting: other, Installed: otherInstalled})
+				return
+			}
+		}
+	}
+
+	for i, version := range incoming {
+		for _, installedVersion := range installed {
+			if installedVersion.ProjectID == version.ProjectID {
+				continue
+			}
+			check(version, &installedVersion, true)
+			check(&installedVersion, version, false)
+		}
+		for j, other := range incoming {
+			if i != j && other.ProjectID != version.ProjectID {
+				check(version, other, false)
+			}
+		}
+	}
+	return conflicts
+}
+
+// function to explain why the installation was blocked
+func printIncompatibilities(conflicts []Incompatibility) {
+	var projectIDs []string
+	for _, conflict := range conflicts {
+		projectIDs = append(projectIDs, conflict.Version.ProjectID, conflict.Conflicting.ProjectID)
+	}
+	projects, _ := fetchProjects(projectIDs)
+
+	title := func(version *modrinth.Version) string {
+		return fmt.Sprintf("%s %s", titleOf(projects, version.ProjectID), version.VersionNumber)
+	}
+
+	fmt.Printf("%sIncompatible mods found:%s\n", Red, Reset)
+	for _, conflict := range conflicts {
+		state := "to be installed"
+		if conflict.Installed {
+			state = "installed"
+		}
+		fmt.Printf("  %s declares it is incompatible with %s (%s)\n", title(conflict.Version), title(conflict.Conflicting), state)
+	}
+	fmt.Println("Nothing was changed, use --force to install anyway")
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+
+	"gorium/modrinth"
+)
+
+// modInfo prints what is known about a mod, without a name a menu of installed mods is shown.
+// Everything comes from the cache when offline.
+func modInfo(modName string, configData Config, backward []bool) {
+	if len(configData.Name) == 0 {
+		fmt.Println(Red + "No profile found, type gorium profile create" + Reset)
+		return
+	}
+
+	installed, err := getInstalledMods(configData.ModsFolder)
+	if err != nil {
+		printError(err)
+		return
+	}
+
+	if modName == "" {
+		if len(installed) == 0 {
+			fmt.Println("There's no mods, type gorium add")
+			return
+		}
+		modName = chooseInstalledMod(installed, "Select the mod you want to see")
+		if modName == "" {
+			return
+		}
+	}
+
+	mod, isInstalled := findInstalledMod(installed, modName)
+	var project *modrinth.Project
+	if isInstalled && mod.Project.ID != "" {
+		project = &mod.Project
+	} else {
+		project = fetchProject(modName)
+		if project == nil {
+			return
+		}
+		mod, isInstalled = installed[project.ID]
+	}
+
+	fmt.Printf("%s%s%s (%s)\n", Bold, project.Title, Reset, project.Slug)
+	if project.Description != "" {
+		fmt.Println(project.Description)
+	}
+	fmt.Println()
+
+	if isInstalled {
+		held := ""
+		if configData.isHeld(project.ID) {
+			held = fmt.Sprintf(" [%sHeld%s]", Yellow, Reset)
+		}
+		fmt.Printf("Installed: %s%s%s (%s)%s\n", Green, mod.Version.VersionNumber, Reset, mod.Filename, held)
+
+		lock := readLockFile(configData.ModsFolder)
+		if locked := lock.findMod(project.ID); locked != nil && locked.Reason == ReasonDependency {
+			projects, _ := fetchProjects([]string{locked.RequiredBy})
+			fmt.Printf("Installed as a dependency of %s\n", titleOf(projects, locked.RequiredBy))
+		}
+	} else {
+		fmt.Println("Installed: no")
+	}
+	fmt.Printf("Channel: %s%s%s\n", Cyan, configData.channelFor(project.ID), Reset)
+
+	versions, err := fetchCompatibleVersions(project.ID, configData, backward)
+	switch {
+	case err != nil:
+		fmt.Println("Latest: unknown")
+	case len(versions) == 0:
+		fmt.Printf("Latest: none for %s %s\n", configData.Loader, configData.GameVersion)
+	case isInstalled && versions[0].ID == mod.Version.ID:
+		fmt.Printf("Latest: %s%s%s (%s), up to date\n", Green, versions[0].VersionNumber, Reset, versions[0].VersionType)
+	default:
+		fmt.Printf("Latest: %s%s%s (%s)\n", Yellow, versions[0].VersionNumber, Reset, versions[0].VersionType)
+	}
+
+	if project.ClientSide != "" || project.ServerSide != "" {
+		fmt.Printf("Client: %s, server: %s\n", project.ClientSide, project.ServerSide)
+	}
+	if project.License.ID != "" {
+		fmt.Printf("License: %s\n", project.License.ID)
+	}
+	if project.Downloads > 0 {
+		fmt.Printf("Downloads: %d\n", project.Downloads)
+	}
+	if project.SourceURL != "" {
+		fmt.Printf("Source: %s\n", project.SourceURL)
+	}
+	fmt.Printf("%s%s%s\n", White, providerFor(project.ID).ProjectURL(project), Reset)
+}
@@ -0,0 +1,343 @@
+package main
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/json"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Mods published outside of Modrinth and CurseForge don't come with a list of game versions and loaders,
+// so they are guessed from the file name and, if it doesn't tell, from the metadata inside the jar.
+
+// JarInfo is what the metadata files of a jar say about where it works
+type JarInfo struct {
+	Loaders []string `json:"loaders"`
+	// GameVersions are the Minecraft version requirements of the jar, in the syntax of its loader.
+	// Any one of them has to be met, an empty list means the jar doesn't say.
+	GameVersions []string `json:"game_versions"`
+	// RangeSyntax is "maven" for Forge and NeoForge jars, "semver" for Fabric and Quilt ones
+	RangeSyntax string `json:"range_syntax"`
+	SHA1        string `json:"sha1,omitempty"`
+	SHA512      string `json:"sha512,omitempty"`
+}
+
+// function to read the loaders and Minecraft requirements of a jar from its metadata files
+func readJarInfo(reader io.ReaderAt, size int64) (JarInfo, error) {
+	var info JarInfo
+	archive, err := zip.NewReader(reader, size)
+	if err != nil {
+		return info, err
+	}
+
+	for _, file := range archive.File {
+		switch file.Name {
+		case "fabric.mod.json":
+			var metadata struct {
+				Depends map[string]json.RawMessage `json:"depends"`
+			}
+			if readJarJSON(file, &metadata) {
+				info.Loaders = append(info.Loaders, "fabric")
+				info.GameVersions = append(info.GameVersions, semverRequirements(metadata.Depends["minecraft"])...)
+				info.RangeSyntax = "semver"
+			}
+		case "quilt.mod.json":
+			var metadata struct {
+				QuiltLoader struct {
+					Depends []json.RawMessage `json:"depends"`
+				} `json:"quilt_loader"`
+			}
+			if readJarJSON(file, &metadata) {
+				info.Loaders = append(info.Loaders, "quilt")
+				for _, dependency := range metadata.QuiltLoader.Depends {
+					var object struct {
+						ID       string          `json:"id"`
+						Versions json.RawMessage `json:"versions"`
+					}
+					if json.Unmarshal(dependency, &object) == nil && object.ID == "minecraft" {
+						info.GameVersions = append(info.GameVersions, semverRequirements(object.Versions)...)
+					}
+				}
+				info.RangeSyntax = "semver"
+			}
+		case "META-INF/mods.toml", "META-INF/neoforge.mods.toml":
+			contents, err := file.Open()
+			if err != nil {
+				continue
+			}
+			versionRange, neoforge := readModsToml(contents)
+			_ = contents.Close()
+			if neoforge || file.Name == "META-INF/neoforge.mods.toml" {
+				info.Loaders = append(info.Loaders, "neoforge")
+			} else {
+				info.Loaders = append(info.Loaders, "forge")
+			}
+			if versionRange != "" {
+				info.GameVersions = append(info.GameVersions, versionRange)
+			}
+			info.RangeSyntax = "maven"
+		}
+	}
+	return info, nil
+}
+
+func readJarJSON(file *zip.File, value any) bool {
+	contents, err := file.Open()
+	if err != nil {
+		return false
+	}
+	defer func(contents io.ReadCloser) {
+		_ = contents.Close()
+	}(contents)
+	return json.NewDecoder(contents).Decode(value) == nil
+}
+
+// function to get the version requirements of a Fabric or Quilt dependency, it's a string,
+// a list of strings any of which is enough, or for Quilt an object with such a list under "any"
+func semverRequirements(raw json.RawMessage) []string {
+	if len(raw) == 0 {
+		return nil
+	}
+	var single string
+	if json.Unmarshal(raw, &single) == nil {
+		return []string{single}
+	}
+	var list []string
+	if json.Unmarshal(raw, &list) == nil {
+		return list
+	}
+	var object struct {
+		Any []string `json:"any"`
+	}
+	if json.Unmarshal(raw, &object) == nil {
+		return object.Any
+	}
+	return nil
+}
+
+var tomlKeyValue = regexp.MustCompile(`^\s*(\w+)\s*=\s*["']([^"']*)["']`)
+
+// function to find the Minecraft version range in a mods.toml file and whether it depends on NeoForge.
+// Only the parts gorium needs are read, it isn't a full TOML parser.
+func readModsToml(contents io.Reader) (string, bool) {
+	var versionRange string
+	var neoforge bool
+	var modID, blockRange string
+	inDependency := false
+
+	endBlock := func() {
+		if inDependency && modID == "minecraft" {
+			versionRange = blockRange
+		}
+		modID, blockRange = "", ""
+	}
+
+	scanner := bufio.NewScanner(contents)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			endBlock()
+			inDependency = strings.HasPrefix(line, "[[dependencies.")
+			continue
+		}
+		match := tomlKeyValue.FindStringSubmatch(line)
+		if match == nil || !inDependency {
+			continue
+		}
+		switch match[1] {
+		case "modId":
+			modID = match[2]
+			if modID == "neoforge" {
+				neoforge = true
+			}
+		case "versionRange":
+			blockRange = match[2]
+		}
+	}
+	endBlock()
+	return versionRange, neoforge
+}
+
+// function to check if a jar works with a game version, jars that don't say are taken to work with all of them
+func (info JarInfo) allowsGameVersion(gameVersion string) bool {
+	if len(info.GameVersions) == 0 {
+		return true
+	}
+	for _, requirement := range info.GameVersions {
+		if info.RangeSyntax == "maven" && mavenRangeAllows(requirement, gameVersion) {
+			return true
+		}
+		if info.RangeSyntax != "maven" && semverAllows(requirement, gameVersion) {
+			return true
+		}
+	}
+	return false
+}
+
+// function to compare two dotted versions like 1.20.4 and 1.21, missing parts count as 0 and
+// anything after a - or + is ignored
+func compareGameVersions(a string, b string) int {
+	partsA := gameVersionParts(a)
+	partsB := gameVersionParts(b)
+	for i := 0; i < max(len(partsA), len(partsB)); i++ {
+		var numberA, numberB int
+		if i < len(partsA) {
+			numberA = partsA[i]
+		}
+		if i < len(partsB) {
+			numberB = partsB[i]
+		}
+		if numberA != numberB {
+			return numberA - numberB
+		}
+	}
+	return 0
+}
+
+func gameVersionParts(version string) []int {
+	version, _, _ = strings.Cut(version, "-")
+	version, _, _ = strings.Cut(version, "+")
+	var parts []int
+	for _, part := range strings.Split(version, ".") {
+		number, err := strconv.Atoi(part)
+		if err != nil {
+			break
+		}
+		parts = append(parts, number)
+	}
+	return parts
+}
+
+// function to check a Fabric style requirement like ">=1.20.5 <1.22", "~1.21", "1.21.x" or "*".
+// All space separated parts have to be met.
+func semverAllows(requirement string, version string) bool {
+	for _, part := range strings.Fields(requirement) {
+		if !semverPartAllows(part, version) {
+			return false
+		}
+	}
+	return true
+}
+
+func semverPartAllows(part string, version string) bool {
+	switch {
+	case part == "*":
+		return true
+	case strings.HasPrefix(part, ">="):
+		return compareGameVersions(version, part[2:]) >= 0
+	case strings.HasPrefix(part, "<="):
+		return compareGameVersions(version, part[2:]) <= 0
+	case strings.HasPrefix(part, ">"):
+		return compareGameVersions(version, part[1:]) > 0
+	case strings.HasPrefix(part, "<"):
+		return compareGameVersions(version, part[1:]) < 0
+	case strings.HasPrefix(part, "~"):
+		// same major and minor, at least the given patch
+		base := gameVersionParts(part[1:])
+		parts := gameVersionParts(version)
+		return compareGameVersions(version, part[1:]) >= 0 && len(parts) > 1 && len(base) > 1 && parts[0] == base[0] && parts[1] == base[1]
+	case strings.HasPrefix(part, "^"):
+		// same major, at least the given version
+		base := gameVersionParts(part[1:])
+		parts := gameVersionParts(version)
+		return compareGameVersions(version, part[1:]) >= 0 && len(parts) > 0 && len(base) > 0 && parts[0] == base[0]
+	case strings.HasSuffix(part, ".x") || strings.HasSuffix(part, ".*"):
+		prefix := part[:len(part)-1]
+		return strings.HasPrefix(version+".", prefix)
+	default:
+		return compareGameVersions(version, strings.TrimPrefix(part, "=")) == 0
+	}
+}
+
+// function to check a Maven version range like "[1.21,1.22)", "[1.21.1]" or "[1.20,1.20.4],[1.21,)".
+// A bare version is only a preference in Maven, so it allows everything.
+func mavenRangeAllows(versionRange string, version string) bool {
+	versionRange = strings.ReplaceAll(versionRange, " ", "")
+	if versionRange == "" || !strings.ContainsAny(versionRange, "[(") {
+		return true
+	}
+
+	for versionRange != "" {
+		end := strings.IndexAny(versionRange, "])")
+		if end < 0 {
+			return false
+		}
+		interval := versionRange[:end+1]
+		versionRange = strings.TrimPrefix(versionRange[end+1:], ",")
+
+		lower, upper, isRange := strings.Cut(interval[1:len(interval)-1], ",")
+		if !isRange {
+			if compareGameVersions(version, lower) == 0 {
+				return true
+			}
+			continue
+		}
+		if lower != "" {
+			comparison := compareGameVersions(version, lower)
+			if comparison < 0 || (comparison == 0 && interval[0] == '(') {
+				continue
+			}
+		}
+		if upper != "" {
+			comparison := compareGameVersions(version, upper)
+			if comparison > 0 || (comparison == 0 && interval[len(interval)-1] == ')') {
+				continue
+			}
+		}
+		return true
+	}
+	return false
+}
+
+var (
+	mcVersionToken   = regexp.MustCompile(`^mc(1\.\d+(?:\.\d+|\.x)?)$`)
+	gameVersionToken = regexp.MustCompile(`^1\.(\d+)(?:\.\d+|\.x)?$`)
+)
+
+// function to guess the loaders and game versions of a jar from its file name, like
+// "tiny-tweaks-2.0.0+mc1.21.1-fabric.jar". Versions written as "mc1.21.1" are trusted over bare ones,
+// since "1.9.0" may just as well be the version of the mod.
+func guessFromFilename(filename string) ([]string, []string) {
+	name := strings.TrimSuffix(strings.ToLower(filename), ".jar")
+	tokens := strings.FieldsFunc(name, func(r rune) bool {
+		return r == '-' || r == '_' || r == '+' || r == ' ' || r == '[' || r == ']' || r == '(' || r == ')'
+	})
+
+	var loaders, mcVersions, bareVersions []string
+	for _, token := range tokens {
+		switch token {
+		case "fabric", "quilt", "forge":
+			loaders = append(loaders, token)
+		case "neoforge", "neoforged", "neo":
+			loaders = append(loaders, "neoforge")
+		}
+		if match := mcVersionToken.FindStringSubmatch(token); match != nil {
+			mcVersions = append(mcVersions, match[1])
+			continue
+		}
+		// Minecraft versions modded with these loaders start at 1.7
+		if match := gameVersionToken.FindStringSubmatch(token); match != nil {
+			if minor, _ := strconv.Atoi(match[1]); minor >= 7 {
+				bareVersions = append(bareVersions, token)
+			}
+		}
+	}
+	if len(mcVersions) > 0 {
+		return slices.Compact(loaders), mcVersions
+	}
+	return slices.Compact(loaders), bareVersions
+}
+
+// function to check if a game version from a file name covers a game version, "1.21" and "1.21.x"
+// are taken to mean every 1.21 release since that's how authors usually mean them
+func filenameVersionAllows(named string, gameVersion string) bool {
+	if named == gameVersion {
+		return true
+	}
+	named = strings.TrimSuffix(named, ".x")
+	return len(gameVersionParts(named)) == 2 && strings.HasPrefix(gameVersion+".", named+".")
+}
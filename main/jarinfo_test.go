@@ -0,0 +1,131 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestMavenRangeAllows(t *testing.T) {
+	tests := []struct {
+		versionRange string
+		version      string
+		want         bool
+	}{
+		{"[1.0,2.0)", "1.0", true},
+		{"[1.0,2.0)", "1.5", true},
+		{"[1.0,2.0)", "2.0", false},
+		{"[1.0,2.0)", "0.9", false},
+		{"(,1.5]", "1.5", true},
+		{"(,1.5]", "1.0", true},
+		{"(,1.5]", "1.5.1", false},
+		{"(1.20,1.21]", "1.20", false},
+		{"(1.20,1.21]", "1.21", true},
+		{"[1.21,)", "1.22.4", true},
+		{"[1.21.1]", "1.21.1", true},
+		{"[1.21.1]", "1.21", false},
+		{"[1.20,1.20.4],[1.21,)", "1.20.2", true},
+		{"[1.20,1.20.4],[1.21,)", "1.20.5", false},
+		{"[1.20,1.20.4],[1.21,)", "1.21.3", true},
+		{"[1.21, 1.22)", "1.21.1", true},
+		// a bare version is only a preference
+		{"1.21", "1.20.1", true},
+		{"", "1.21", true},
+		{"[1.21", "1.21", false},
+	}
+	for _, test := range tests {
+		if got := mavenRangeAllows(test.versionRange, test.version); got != test.want {
+			t.Errorf("mavenRangeAllows(%q, %q) = %v, want %v", test.versionRange, test.version, got, test.want)
+		}
+	}
+}
+
+func TestSemverAllows(t *testing.T) {
+	tests := []struct {
+		requirement string
+		version     string
+		want        bool
+	}{
+		{">=1.20.5 <1.22", "1.21.1", true},
+		{">=1.20.5 <1.22", "1.20.4", false},
+		{">=1.20.5 <1.22", "1.22", false},
+		{">1.21 <=1.21.3", "1.21", false},
+		{">1.21 <=1.21.3", "1.21.3", true},
+		{">1.21 <=1.21.3", "1.21.4", false},
+		{"~1.21", "1.21.1", true},
+		{"~1.21", "1.22", false},
+		{"~1.21", "1.20.6", false},
+		{"~1.21.1", "1.21", false},
+		{"~1.21.1", "1.21.4", true},
+		{"^1.20", "1.21.1", true},
+		{"^1.20", "1.19.4", false},
+		{"^1.20", "2.0", false},
+		{"1.21.x", "1.21", true},
+		{"1.21.x", "1.21.1", true},
+		{"1.21.x", "1.210", false},
+		{"1.21.x", "1.2", false},
+		{"1.21.*", "1.21.4", true},
+		{"*", "1.8.9", true},
+		{"=1.21.1", "1.21.1", true},
+		{"1.21.1", "1.21.1", true},
+		{"1.21.1", "1.21", false},
+		{"1.21.1-rc.1", "1.21.1", true},
+		{"", "1.21", true},
+	}
+	for _, test := range tests {
+		if got := semverAllows(test.requirement, test.version); got != test.want {
+			t.Errorf("semverAllows(%q, %q) = %v, want %v", test.requirement, test.version, got, test.want)
+		}
+	}
+}
+
+func TestGuessFromFilename(t *testing.T) {
+	tests := []struct {
+		filename     string
+		loaders      []string
+		gameVersions []string
+	}{
+		{"tiny-tweaks-2.0.0+mc1.21.1-fabric.jar", []string{"fabric"}, []string{"1.21.1"}},
+		{"sodium-fabric-0.5.11+mc1.21.jar", []string{"fabric"}, []string{"1.21"}},
+		{"fabric-api-0.104.0+1.21.1.jar", []string{"fabric"}, []string{"1.21.1"}},
+		{"Xaeros_Minimap_24.4.0_Fabric_1.21.jar", []string{"fabric"}, []string{"1.21"}},
+		{"jei-1.21.1-neoforge-19.19.0.221.jar", []string{"neoforge"}, []string{"1.21.1"}},
+		{"create-1.20.1-0.5.1.f.jar", nil, []string{"1.20.1"}},
+		{"[1.21.x] Forge - Cool Mod (Quilt).jar", []string{"forge", "quilt"}, []string{"1.21.x"}},
+		{"Mod-NeoForge-1.21.JAR", []string{"neoforge"}, []string{"1.21"}},
+		{"mod-neo-neoforged-1.21.jar", []string{"neoforge"}, []string{"1.21"}},
+		// versions written with mc are trusted over bare ones, which may be the version of the mod
+		{"coolmod-1.9.0-mc1.20.1.jar", nil, []string{"1.20.1"}},
+		{"coolmod-1.9.0.jar", nil, []string{"1.9.0"}},
+		// Minecraft versions before 1.7 aren't modded with these loaders
+		{"coolmod-1.5.2.jar", nil, nil},
+		{"plain-mod-3.1.jar", nil, nil},
+		{"forgery-1.21.jar", nil, []string{"1.21"}},
+	}
+	for _, test := range tests {
+		loaders, gameVersions := guessFromFilename(test.filename)
+		if !slices.Equal(loaders, test.loaders) || !slices.Equal(gameVersions, test.gameVersions) {
+			t.Errorf("guessFromFilename(%q) = %v, %v, want %v, %v", test.filename, loaders, gameVersions, test.loaders, test.gameVersions)
+		}
+	}
+}
+
+func TestFilenameVersionAllows(t *testing.T) {
+	tests := []struct {
+		named       string
+		gameVersion string
+		want        bool
+	}{
+		{"1.21.1", "1.21.1", true},
+		{"1.21", "1.21", true},
+		{"1.21", "1.21.1", true},
+		{"1.21.x", "1.21.4", true},
+		{"1.21.1", "1.21", false},
+		{"1.21.1", "1.21.2", false},
+		{"1.2", "1.21", false},
+	}
+	for _, test := range tests {
+		if got := filenameVersionAllows(test.named, test.gameVersion); got != test.want {
+			t.Errorf("filenameVersionAllows(%q, %q) = %v, want %v", test.named, test.gameVersion, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// The jar cache keeps every downloaded jar once, named by its SHA512 hash, and is shared by all profiles.
+// Jars are put into mods folders as reflinks or hardlinks when the filesystem allows it, copies otherwise.
+// A cached jar is checked against its hash before it's used, so a damaged one is never installed.
+
+func getJarCacheDir() string {
+	return path.Join(getCacheDir(), "jars")
+}
+
+// function to get where a jar with the given SHA512 hash is kept in the jar cache
+func getCachedJarPath(sha512 string) string {
+	return path.Join(getJarCacheDir(), sha512[:2], sha512+".jar")
+}
+
+// storeJar adds a verified jar to the jar cache, errors are ignored since it's only a cache
+func storeJar(filePath string, sha512 string) {
+	if len(sha512) < 2 {
+		return
+	}
+	cachedPath := getCachedJarPath(sha512)
+	if dirExists(cachedPath) {
+		return
+	}
+	if err := os.MkdirAll(path.Dir(cachedPath), 0755); err != nil {
+		return
+	}
+	_, _ = linkFile(filePath, cachedPath)
+}
+
+// restoreJar puts a jar from the jar cache at filePath and tells how it was done, returns false
+// if it isn't cached or the cached copy is damaged
+func restoreJar(sha512 string, filePath string) (string, bool) {
+	if len(sha512) < 2 {
+		return "", false
+	}
+	cachedPath := getCachedJarPath(sha512)
+	if !dirExists(cachedPath) {
+		return "", false
+	}
+	if hashFileSHA512(cachedPath) != sha512 {
+		_ = os.Remove(cachedPath)
+		return "", false
+	}
+	method, err := linkFile(cachedPath, filePath)
+	return method, err == nil
+}
+
+// linkFile makes destination have the contents of source, using the cheapest way the filesystem supports.
+// It returns "reflink", "hardlink" or "copy". An existing destination is replaced.
+func linkFile(source string, destination string) (string, error) {
+	temp := path.Join(path.Dir(destination), "."+path.Base(destination)+".link")
+	_ = os.Remove(temp)
+
+	method := "reflink"
+	err := reflinkFile(source, temp)
+	if err != nil {
+		method = "hardlink"
+		err = os.Link(source, temp)
+	}
+	if err != nil {
+		method = "copy"
+		err = copyFile(source, temp)
+	}
+	if err == nil {
+		err = os.Rename(temp, destination)
+	}
+	if err != nil {
+		_ = os.Remove(temp)
+		return "", err
+	}
+	return method, nil
+}
+
+// function to copy a file
+func copyFile(source string, destination string) error {
+	in, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer func(in *os.File) {
+		_ = in.Close()
+	}(in)
+
+	out, err := os.OpenFile(destination, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(out, in)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// CachedJar is a jar in the jar cache
+type CachedJar struct {
+	SHA512 string
+	Path   string
+	Size   int64
+}
+
+// function to list the jars in the jar cache
+func getCachedJars() ([]CachedJar, error) {
+	var jars []CachedJar
+	err := filepath.WalkDir(getJarCacheDir(), func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".jar") {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		jars = append(jars, CachedJar{
+			SHA512: strings.TrimSuffix(entry.Name(), ".jar"),
+			Path:   filePath,
+			Size:   info.Size(),
+		})
+		return nil
+	})
+	return jars, err
+}
+
+// function to get the SHA512 hashes of every jar some profile uses, from the lockfiles and the mods folders
+func getUsedJarHashes() map[string]bool {
+	used := map[string]bool{}
+	configPath, _ := getConfigPath()
+	if !dirExists(configPath) {
+		return used
+	}
+	for _, profile := range readFullConfig(configPath).Profiles {
+		if dirExists(getLockFilePath(profile.ModsFolder)) {
+			lock := readLockFile(profile.ModsFolder)
+			for _, mod := range lock.Mods {
+				used[mod.SHA512] = true
+			}
+			for _, mod := range lock.Unmanaged {
+				used[mod.SHA512] = true
+			}
+		}
+		if !dirExists(profile.ModsFolder) {
+			continue
+		}
+		for hash := range getSHA512FilesFromDirectory(profile.ModsFolder) {
+			used[hash] = true
+		}
+	}
+	return used
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+	"sort"
+
+	"gorium/modrinth"
+)
+
+// LockFileName is the name of the lockfile kept in the mods folder of every profile
+const LockFileName = "gorium.lock"
+
+// Install reasons recorded in the lockfile
+const (
+	ReasonRequested  = "requested"
+	ReasonDependency = "dependency"
+)
+
+type LockFile struct {
+	Mods      []LockedMod    `json:"mods"`
+	Unmanaged []UnmanagedMod `json:"unmanaged,omitempty"` // jars added from a file or an address
+}
+
+type LockedMod struct {
+	ProjectID  string `json:"project_id"`
+	Provider   string `json:"provider,omitempty"` // where the mod comes from, empty for Modrinth
+	VersionID  string `json:"version_id"`
+	Filename   string `json:"filename"`
+	URL        string `json:"url"`
+	SHA512     string `json:"sha512"`
+	Reason     string `json:"reason"`
+	RequiredBy string `json:"required_by,omitempty"`
+}
+
+func getLockFilePath(modsPath string) string {
+	return path.Join(modsPath, LockFileName)
+}
+
+// function to read the lockfile of a profile, returns an empty one if there's none yet
+func readLockFile(modsPath string) LockFile {
+	var lock LockFile
+
+	lockPath := getLockFilePath(modsPath)
+	if !dirExists(lockPath) {
+		return lock
+	}
+
+	lockData, err := os.ReadFile(lockPath)
+	checkError(err)
+
+	err = json.Unmarshal(lockData, &lock)
+	checkError(err)
+	return lock
+}
+
+// function to write the lockfile, mods are sorted so the file stays diffable
+func writeLockFile(modsPath string, lock LockFile) {
+	sort.Slice(lock.Mods, func(i, j int) bool {
+		return lock.Mods[i].Filename < lock.Mods[j].Filename
+	})
+	if lock.Mods == nil {
+		lock.Mods = []LockedMod{}
+	}
+
+	jsonData, _ := json.MarshalIndent(lock, "", "  ")
+
+	err := os.WriteFile(getLockFilePath(modsPath), append(jsonData, '\n'), 0644)
+	checkError(err)
+}
+
+func (lock *LockFile) findMod(projectID string) *LockedMod {
+	for i := range lock.Mods {
+		if lock.Mods[i].ProjectID == projectID {
+			return &lock.Mods[i]
+		}
+	}
+	return nil
+}
+
+// setMod adds a mod to the lockfile or replaces the entry of the same project
+func (lock *LockFile) setMod(mod LockedMod) {
+	if existing := lock.findMod(mod.ProjectID); existing != nil {
+		*existing = mod
+		return
+	}
+	lock.Mods = append(lock.Mods, mod)
+}
+
+func (lock *LockFile) removeMod(projectID string) {
+	for i := range lock.Mods {
+		if lock.Mods[i].ProjectID == projectID {
+			lock.Mods = append(lock.Mods[:i], lock.Mods[i+1:]...)
+			return
+		}
+	}
+}
+
+// function to make a lockfile entry for a file of a version
+func lockedModFromVersion(version *modrinth.Version, file modrinth.File, reason string, requiredBy string) LockedMod {
+	provider := providerFor(version.ProjectID)
+	providerName := ""
+	if !isDefaultProvider(provider) {
+		providerName = provider.Name()
+	}
+	return LockedMod{
+		ProjectID:  version.ProjectID,
+		Provider:   providerName,
+		VersionID:  version.ID,
+		Filename:   file.Filename,
+		URL:        file.URL,
+		SHA512:     file.Hashes.SHA512,
+		Reason:     reason,
+		RequiredBy: requiredBy,
+	}
+}
+
+// function to find the file of a version with the given SHA512 hash, falls back to the primary file
+func fileWithHash(version *modrinth.Version, hash string) modrinth.File {
+	for _, file := range version.Files {
+		if file.Hashes.SHA512 == hash {
+			return file
+		}
+	}
+	return primaryFile(version)
+}
@@ -3,11 +3,12 @@ package main
 //	importing libraries
 import (
 	"bufio"
-	"bytes"
 	"crypto/rand"
+	"crypto/sha1"
 	"crypto/sha512"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
@@ -23,6 +24,7 @@ import (
 	"time"
 
 	"gorium/cli"
+	"gorium/modrinth"
 
 	"golang.org/x/term"
 )
@@ -34,13 +36,28 @@ const FullVersion = "Gorium " + ProgramVersion
 var helpStrings = []string{
 	"Use: gorium <command>",
 	"",
-	"gorium add <mod slug/id> - add mod",
+	"gorium add <mod slug/id>[@version] [--force] - add mod",
+	"gorium add <mod slug/id>@ - choose version to add",
+	"gorium add <path/url of a jar> - add a jar gorium doesn't manage, it won't be updated",
+	"gorium add gh:<owner>/<repo> [--asset <pattern>] - add mod from GitHub releases, pattern picks the asset like *-fabric-*.jar",
+	"gorium cache <clear/stats/prune> - remove cached responses, show cache usage or remove unused jars",
+	"gorium channel [release/beta/alpha/default] [mod] - set release channel",
+	"gorium export mrpack [--loader-version <version>] [--output <file>] [--no-overrides] - write the profile as a Modrinth modpack",
 	"gorium help - display this text",
+	"gorium hold/unhold [mod slug/id] - keep mod at its current version",
+	"gorium import <pack.mrpack> --dir <instance folder> [--side client/server] - install a Modrinth modpack as a new profile and switch to it",
+	"gorium info [mod slug/id] - show details of a mod",
 	"gorium list - list installed mods",
 	"gorium profile <create/delete/switch/list>",
-	"gorium search - search mods through Modrinth",
-	"gorium upgrade - update mods to latest version",
+	"gorium remove [mod slug/id/jar name] [--deps] - remove mod",
+	"gorium search <query> [--force] - search mods",
+	"gorium sync - make mods folder match gorium.lock",
+	"gorium token [set/remove] - manage the Modrinth personal access token",
+	"gorium upgrade [--force] - update mods to latest version",
 	"gorium version - display current version of Gorium",
+	"",
+	"--offline works with every command, only cached data is used then",
+	"Mods and search queries can start with their source, mr: for Modrinth or cf: for CurseForge, like cf:jei",
 }
 
 var licenseStrings = []string{
@@ -54,50 +71,28 @@ var licenseStrings = []string{
 }
 
 type Config struct {
-	Active      string `json:"active"`
-	Name        string `json:"name"`
-	ModsFolder  string `json:"modsfolder"`
-	GameVersion string `json:"gameversion"`
-	Loader      string `json:"loader"`
-	Hash        string `json:"hash"`
+	Active           string            `json:"active"`
+	Name             string            `json:"name"`
+	ModsFolder       string            `json:"modsfolder"`
+	GameVersion      string            `json:"gameversion"`
+	Loader           string            `json:"loader"`
+	LoaderVersion    string            `json:"loaderversion,omitempty"` // only needed to export modpacks
+	Channel          string            `json:"channel,omitempty"`
+	ChannelOverrides map[string]string `json:"channeloverrides,omitempty"`
+	Held             []string          `json:"held,omitempty"`
+	GitHubSources    []GitHubSource    `json:"githubsources,omitempty"`
+	Hash             string            `json:"hash"`
 }
 
 type MultiConfig struct {
-	Profiles []Config
-}
-
-type File struct {
-	URL      string `json:"url"`
-	Filename string `json:"filename"`
-}
-
-type Root struct {
-	ProjectID     string   `json:"project_id"`
-	Files         []File   `json:"files"`
-	DatePublished string   `json:"date_published"`
-	Title         string   `json:"title"`
-	Categories    []string `json:"categories"`
-	ProjectType   string   `json:"project_type"`
-	Versions      []string `json:"versions"`
-	Name          string   `json:"name"`
-}
-type SearchRoot struct {
-	Hits []Root `json:"hits"`
-}
-
-type HashesToSend struct {
-	Hashes       []string `json:"hashes"`
-	Algorithm    string   `json:"algorithm"`
-	Loaders      []string `json:"loaders"`
-	GameVersions []string `json:"game_versions"`
-}
-
-type Version struct {
-	GameVersions  []string  `json:"game_versions"`
-	VersionNumber string    `json:"version_number"`
-	Loaders       []string  `json:"loaders"`
-	Files         []File    `json:"files"`
-	DatePublished time.Time `json:"date_published"`
+	Profiles     []Config
+	MaxDownloads int    `json:"maxdownloads,omitempty"`
+	ModrinthAPI  string `json:"modrinthapi,omitempty"`
+	CacheTTL     string `json:"cachettl,omitempty"`
+	// CurseForge needs an API key, CurseForgeAPI overrides its address like ModrinthAPI
+	CurseForgeKey string `json:"curseforgekey,omitempty"`
+	CurseForgeAPI string `json:"curseforgeapi,omitempty"`
+	GitHubAPI     string `json:"githubapi,omitempty"`
 }
 
 // console colors and format
@@ -152,19 +147,41 @@ func main() {
 	getProject := flag.NewFlagSet("add", flag.ExitOnError)
 	createProfile := flag.NewFlagSet("profile", flag.ExitOnError)
 	searchMod := flag.NewFlagSet("search", flag.ExitOnError)
+	upgradeMods := flag.NewFlagSet("upgrade", flag.ExitOnError)
+	removeMods := flag.NewFlagSet("remove", flag.ExitOnError)
+
+	forceAdd := getProject.Bool("force", false, "install even if mods are incompatible")
+	assetFlag := getProject.String("asset", "", "pattern of the release assets to install from GitHub")
+	forceSearch := searchMod.Bool("force", false, "install even if mods are incompatible")
+	forceUpgrade := upgradeMods.Bool("force", false, "upgrade even if mods are incompatible")
+	removeDependencies := removeMods.Bool("deps", false, "also remove dependencies nothing else needs")
+
+	os.Args = parseOfflineFlag(os.Args)
 
 	if len(os.Args) < 2 {
 		displaySimpleText(licenseStrings)
 		return
 	}
 
+	switch os.Args[1] {
+	case "add", "search", "upgrade", "import":
+		// a jar on disk can be added without a connection
+		localAdd := os.Args[1] == "add" && len(os.Args) > 2 && isLocalJar(os.Args[2])
+		if !localAdd && !requireOnline(os.Args[1]) {
+			return
+		}
+	}
+
 	switch os.Args[1] {
 	case "version":
 		fmt.Println("Gorium", ProgramVersion)
 		return
 	case "add":
-		err := getProject.Parse(os.Args[2:])
-		checkError(err)
+		args := parseFlags(getProject, os.Args[2:])
+		if len(args) < 1 {
+			fmt.Println(Red + "Usage: gorium add <mod slug/id>" + Reset)
+			return
+		}
 
 		configPath, _ := getConfigPath()
 		if !dirExists(configPath) {
@@ -173,19 +190,36 @@ func main() {
 		}
 		configData := readConfig(configPath)
 
-		gameVersion := configData.GameVersion
-		loader := configData.Loader
-		modsPath := configData.ModsFolder
+		if isJarURL(args[0]) || isLocalJar(args[0]) {
+			addUnmanaged(args[0], configData)
+			return
+		}
 
-		modName := os.Args[2]
+		modName, versionName, pinned := strings.Cut(args[0], "@")
 
-		latestVersion := fetchLatestVersion(modName, gameVersion, loader, backward)
-		if latestVersion == nil {
+		if *assetFlag != "" {
+			if !strings.HasPrefix(modName, "gh:") {
+				fmt.Println(Red + "--asset only works with mods from GitHub, like gh:owner/repo" + Reset)
+				return
+			}
+			githubAssetPatterns[strings.ToLower(strings.TrimPrefix(modName, "gh:"))] = *assetFlag
+		}
+
+		var versionToInstall *modrinth.Version
+		if pinned {
+			versionToInstall = fetchSpecificVersion(modName, versionName, configData, backward)
+		} else {
+			versionToInstall = fetchLatestVersion(modName, configData, backward)
+		}
+		if versionToInstall == nil {
 			return
 		}
+		if providerFor(versionToInstall.ProjectID).Name() == "github" {
+			configData.addGitHubSource(strings.TrimPrefix(versionToInstall.ProjectID, "gh:"), *assetFlag)
+			saveProfile(configData)
+		}
 
-		err = downloadFile(latestVersion.Files[0].URL, modsPath, latestVersion.Files[0].Filename)
-		checkError(err)
+		installVersions([]*modrinth.Version{versionToInstall}, configData, backward, *forceAdd)
 		return
 
 	case "profile":
@@ -211,16 +245,58 @@ func main() {
 		return
 
 	case "upgrade":
-		upgrade()
+		parseFlags(upgradeMods, os.Args[2:])
+		upgrade(*forceUpgrade, backward)
 		return
 	case "list":
 		listMods()
 		return
+	case "sync":
+		syncMods()
+		return
+	case "info":
+		modName := ""
+		if len(os.Args) > 2 {
+			modName = os.Args[2]
+		}
+		modInfo(modName, configData, backward)
+		return
+	case "token":
+		tokenCommand(os.Args[2:])
+		return
+	case "cache":
+		cacheCommand(os.Args[2:])
+		return
+	case "export":
+		exportCommand(os.Args[2:])
+		return
+	case "import":
+		importCommand(os.Args[2:])
+		return
+	case "channel":
+		channelCommand(os.Args[2:])
+		return
+	case "hold":
+		holdCommand(os.Args[2:], true)
+		return
+	case "unhold":
+		holdCommand(os.Args[2:], false)
+		return
+	case "remove":
+		args := parseFlags(removeMods, os.Args[2:])
+		modName := ""
+		if len(args) > 0 {
+			modName = args[0]
+		}
+		removeMod(modName, *removeDependencies)
+		return
 	case "search":
-		err := searchMod.Parse(os.Args[2:])
-		checkError(err)
-		modName := os.Args[2]
-		Search(modName, backward)
+		args := parseFlags(searchMod, os.Args[2:])
+		if len(args) < 1 {
+			fmt.Println(Red + "Usage: gorium search <query>" + Reset)
+			return
+		}
+		Search(args[0], backward, *forceSearch)
 	case "help":
 		displaySimpleText(helpStrings)
 	case "testing":
@@ -240,30 +316,6 @@ func main() {
 	}
 }
 
-func sendModrinthAPIRequest(url string, requestType string, contents io.Reader, contentType string) []byte {
-	client := http.Client{
-		Timeout: time.Second * 5,
-	}
-
-	req, _ := http.NewRequest(requestType, url, contents)
-	req.Header.Set("User-Agent", FullVersion)
-	if contents != nil {
-		req.Header.Set("Content-Type", contentType)
-	}
-
-	resp, err := client.Do(req)
-	checkError(err)
-
-	defer func(Body io.ReadCloser) {
-		err := Body.Close()
-		checkError(err)
-	}(resp.Body)
-
-	body, _ := io.ReadAll(resp.Body)
-
-	return body
-}
-
 func displaySimpleText(stringsToDisplay []string) {
 	width, height, err := term.GetSize(int(os.Stdout.Fd()))
 	if err != nil {
@@ -315,17 +367,50 @@ func displaySimpleText(stringsToDisplay []string) {
 
 //	Function for fetching latest version
 
-func fetchLatestVersion(modName string, gameVersion string, loader string, backward []bool) *Version {
+func fetchLatestVersion(modName string, configData Config, backward []bool) *modrinth.Version {
+	versions, err := fetchLoaderVersions(modName, configData, backward)
+	if err != nil {
+		printModError(modName, err)
+		return nil
+	}
+	if len(versions) == 0 {
+		fmt.Println(Red + "No versions found" + Reset)
+		return nil
+	}
+	projectID := versions[0].ProjectID
+	filteredVersions := filterByChannel(versions, configData.channelFor(projectID))
+	if len(filteredVersions) == 0 {
+		printNoVersionsFound(configData, projectID)
+		return nil
+	}
+	return &filteredVersions[0]
+}
 
-	urlProject := fmt.Sprintf("https://api.modrinth.com/v2/project/%s/version", modName)
+// function to fetch all versions of a mod that work with the profile and its channel, newest first
+func fetchCompatibleVersions(modName string, configData Config, backward []bool) ([]modrinth.Version, error) {
+	filteredVersions, err := fetchLoaderVersions(modName, configData, backward)
+	if err != nil || len(filteredVersions) == 0 {
+		return filteredVersions, err
+	}
+	return filterByChannel(filteredVersions, configData.channelFor(filteredVersions[0].ProjectID)), nil
+}
 
-	body := sendModrinthAPIRequest(urlProject, "GET", nil, "")
+// function to fetch all versions of a mod for the game version and loader of the profile, whatever
+// their channel, newest first
+func fetchLoaderVersions(modName string, configData Config, backward []bool) ([]modrinth.Version, error) {
+	gameVersion := configData.GameVersion
+	loader := configData.Loader
 
-	var versions []Version
-	err := json.Unmarshal(body, &versions)
-	checkError(err)
+	provider, name, err := splitProvider(modName)
+	if err != nil {
+		return nil, err
+	}
+	versions, err := provider.Versions(name, gameVersion)
+	if err != nil {
+		return nil, err
+	}
 
-	var filteredVersions []Version
+	var filteredVersions []modrinth.Version
 
 	for _, version := range versions {
 		if !backward[1] && !backward[0] {
@@ -341,63 +426,240 @@ func fetchLatestVersion(modName string, gameVersion string, loader string, backw
 			}
 		}
 	}
+	sort.Slice(filteredVersions, func(i, j int) bool {
+		return filteredVersions[i].DatePublished.After(filteredVersions[j].DatePublished)
+	})
+	return filteredVersions, nil
+}
+
+// function to print an error that happened while looking up a mod
+func printModError(modName string, err error) {
+	if isNotFound(err) {
+		fmt.Printf("%sMod %s not found%s\n", Red, modName, Reset)
+		return
+	}
+	printError(err)
+}
+
+// function to report that no versions are available, mentioning the channel if it's restricted
+func printNoVersionsFound(configData Config, projectID string) {
+	channel := configData.channelFor(projectID)
+	if channel == ChannelAlpha {
+		fmt.Println(Red + "No versions found" + Reset)
+		return
+	}
+	fmt.Printf("%sNo versions found on the %s channel, see gorium channel%s\n", Red, channel, Reset)
+}
+
+// function to fetch a specific version of a mod by its version number or ID.
+// An empty versionName lets the user choose from all compatible versions.
+// The version is picked explicitly, so versions outside the channel are offered too.
+func fetchSpecificVersion(modName string, versionName string, configData Config, backward []bool) *modrinth.Version {
+	filteredVersions, err := fetchLoaderVersions(modName, configData, backward)
+	if err != nil {
+		printModError(modName, err)
+		return nil
+	}
 	if len(filteredVersions) == 0 {
 		fmt.Println(Red + "No versions found" + Reset)
 		return nil
 	}
-	sort.Slice(filteredVersions, func(i, j int) bool {
-		return filteredVersions[i].DatePublished.After(filteredVersions[j].DatePublished)
-	})
-	return &filteredVersions[0]
+
+	if versionName == "" {
+		menu := cli.NewMenu("Choose version")
+		for _, version := range filteredVersions {
+			menu.AddItem(fmt.Sprintf("%s %s[%s%s%s] [%s%s%s]", version.VersionNumber, Reset, Cyan, version.VersionType, Reset, White, version.DatePublished.Format(time.DateOnly), Reset), version.ID)
+		}
+		versionName = menu.Display()
+		if versionName == "" {
+			return nil
+		}
+	}
+
+	for i, version := range filteredVersions {
+		if version.ID == versionName || version.VersionNumber == versionName {
+			return &filteredVersions[i]
+		}
+	}
+	fmt.Printf("%sVersion %s of %s not found for %s %s%s\n", Red, versionName, modName, configData.Loader, configData.GameVersion, Reset)
+	return nil
+}
+
+// how many times a download is resumed before giving up
+const downloadAttempts = 3
+
+// function to download file from url. The file is written to a hidden .part file next to its destination
+// and only gets its real name once it is verified, so a broken download never looks like a mod.
+// A .part file left by an interrupted download is resumed if the server supports it.
+// Jars already in the jar cache aren't downloaded again.
+func downloadFile(url string, modsPath string, filename string, expected modrinth.Hashes, progress *FileProgress) error {
+	if !dirExists(modsPath) {
+		err := os.Mkdir(modsPath, 0755)
+		checkError(err)
+	}
+
+	partPath := path.Join(modsPath, "."+filename+".part")
+	filePath := path.Join(modsPath, filename)
+
+	if method, ok := restoreJar(expected.SHA512, filePath); ok {
+		progress.fromCache(filePath, method)
+		return nil
+	}
+	if isOffline() {
+		return fmt.Errorf("%s isn't in the jar cache and can't be downloaded offline", filename)
+	}
+
+	var err error
+	for attempt := 1; attempt <= downloadAttempts; attempt++ {
+		var retry bool
+		retry, err = resumeDownload(url, partPath, progress)
+		if err == nil || !retry {
+			break
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("error downloading %s: %w", filename, err)
+	}
+
+	hashes := hashFileSums(partPath)
+	if expected.SHA256 != "" {
+		hashes.SHA256, err = sha256File(partPath)
+		checkError(err)
+	}
+	if (expected.SHA512 != "" && hashes.SHA512 != expected.SHA512) || (expected.SHA1 != "" && hashes.SHA1 != expected.SHA1) ||
+		(expected.SHA256 != "" && hashes.SHA256 != expected.SHA256) {
+		_ = os.Remove(partPath)
+		return fmt.Errorf("%s doesn't match its hash, the file was deleted", filename)
+	}
+
+	err = os.Rename(partPath, filePath)
+	if err != nil {
+		return fmt.Errorf("error moving %s into place: %w", filename, err)
+	}
+
+	storeJar(filePath, hashes.SHA512)
+	return nil
 }
 
-// function to download file from url
-func downloadFile(url string, modsPath string, filename string) error {
-	response, err := http.Get(url)
+// resumeDownload appends the rest of the file to partPath, using a Range request if some of it is there already.
+// It returns true along with an error if trying again may help.
+func resumeDownload(url string, partPath string, progress *FileProgress) (bool, error) {
+	var offset int64
+	if info, err := os.Stat(partPath); err == nil {
+		offset = info.Size()
+	}
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("User-Agent", FullVersion)
+	if modrinthClient.Token != "" && modrinth.IsModrinthHost(req.URL) {
+		req.Header.Set("Authorization", modrinthClient.Token)
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	response, err := http.DefaultClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("error downloading the file: %w", err)
+		return true, err
 	}
 	defer func(Body io.ReadCloser) {
 		err := Body.Close()
 		checkError(err)
 	}(response.Body)
 
-	if !dirExists(modsPath) {
-		err := os.Mkdir(modsPath, 0755)
-		checkError(err)
+	flags := os.O_CREATE | os.O_WRONLY
+	switch response.StatusCode {
+	case http.StatusPartialContent:
+		flags |= os.O_APPEND
+		progress.start(response.ContentLength, offset)
+	case http.StatusOK:
+		// the server ignored the range, so start over
+		flags |= os.O_TRUNC
+		progress.start(response.ContentLength, 0)
+	case http.StatusRequestedRangeNotSatisfiable:
+		// the partial file is no good for this url anymore
+		_ = os.Remove(partPath)
+		return true, errors.New(response.Status)
+	default:
+		retry := response.StatusCode == http.StatusTooManyRequests || response.StatusCode >= 500
+		return retry, errors.New(response.Status)
 	}
 
-	file, err := os.Create(path.Join(modsPath, filename))
+	file, err := os.OpenFile(partPath, flags, 0644)
 	if err != nil {
-		return fmt.Errorf("error creating file: %w", err)
+		return false, err
 	}
-	defer func(file *os.File) {
-		err := file.Close()
-		checkError(err)
-	}(file)
 
-	fmt.Printf("[Downloading] [%s%s%s]\n", Cyan, filename, Reset)
+	written, err := io.Copy(file, progress.reader(response.Body))
+	closeErr := file.Close()
+	if err != nil {
+		return true, err
+	}
+	if closeErr != nil {
+		return false, closeErr
+	}
+	if response.ContentLength >= 0 && written != response.ContentLength {
+		return true, fmt.Errorf("connection closed after %d of %d bytes", written, response.ContentLength)
+	}
+	return false, nil
+}
 
-	_, err = io.Copy(file, response.Body)
-	checkError(err)
+// default number of downloads running at the same time
+const defaultMaxDownloads = 6
 
-	return nil
+// function to get how many downloads may run at the same time
+func getMaxDownloads() int {
+	configPath, _ := getConfigPath()
+	if !dirExists(configPath) {
+		return defaultMaxDownloads
+	}
+	maxDownloads := readFullConfig(configPath).MaxDownloads
+	if maxDownloads < 1 {
+		return defaultMaxDownloads
+	}
+	return maxDownloads
 }
 
-func downloadFilesConcurrently(modsPath string, urls []map[string]string) {
+// function to download files in parallel with a limited number of workers,
+// every failure is reported and all of them are returned together
+func downloadFilesConcurrently(modsPath string, urls []map[string]string) error {
 	var wg sync.WaitGroup
-	wg.Add(len(urls))
+	var mutex sync.Mutex
+	var errs []error
 
-	for _, urlMap := range urls {
-		go func(urlMap map[string]string) {
+	progress := newDownloadProgress(len(urls))
+	jobs := make(chan map[string]string)
+
+	workers := min(getMaxDownloads(), len(urls))
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
 			defer wg.Done()
-			if err := downloadFile(urlMap["url"], modsPath, urlMap["filename"]); err != nil {
-				log.Printf("%sError: %s%s", Red, err.Error(), Reset)
+			for urlMap := range jobs {
+				file := progress.addFile(urlMap["filename"])
+				expected := modrinth.Hashes{SHA1: urlMap["sha1"], SHA512: urlMap["sha512"], SHA256: urlMap["sha256"]}
+				err := downloadFile(urlMap["url"], modsPath, urlMap["filename"], expected, file)
+				file.end(err)
+				if err != nil {
+					mutex.Lock()
+					errs = append(errs, err)
+					mutex.Unlock()
+				}
 			}
-		}(urlMap)
+		}()
+	}
+
+	for _, urlMap := range urls {
+		jobs <- urlMap
 	}
+	close(jobs)
 
 	wg.Wait()
+	progress.finish()
+	return errors.Join(errs...)
 }
 
 func dirExists(path string) bool {
@@ -412,17 +674,23 @@ func dirExists(path string) bool {
 }
 
 func createConfig() {
-	folder, mineVersion, loader, name, hash := getConfigDataToWrite()
+	folder, mineVersion, loader, channel, name, hash := getConfigDataToWrite()
 
 	newConfig := Config{
 		ModsFolder:  path.Join(folder, ""),
 		GameVersion: mineVersion,
 		Loader:      loader,
+		Channel:     channel,
 		Name:        name,
 		Active:      "*",
 		Hash:        hash,
 	}
 
+	addProfile(newConfig)
+}
+
+// function to add a profile to the config and make it the active one
+func addProfile(newConfig Config) {
 	configPath, _ := getConfigPath()
 
 	oldConfig := readFullConfig(configPath)
@@ -431,6 +699,7 @@ func createConfig() {
 		oldConfig.Profiles[i].Active = ""
 	}
 
+	newConfig.Active = "*"
 	oldConfig.Profiles = append(oldConfig.Profiles, newConfig)
 
 	jsonData, _ := json.MarshalIndent(oldConfig, "", "  ")
@@ -439,13 +708,14 @@ func createConfig() {
 	checkError(err)
 }
 
-func getConfigDataToWrite() (string, string, string, string, string) {
+func getConfigDataToWrite() (string, string, string, string, string, string) {
 	var folder string
 	var mineVersion string
 	var loader string
+	var channel string
 	var name string
 	var hash string
-	for i := 0; i < 4; {
+	for i := 0; i < 5; {
 		switch i {
 		case 0:
 			fmt.Print("Enter mods folder path: ")
@@ -470,16 +740,23 @@ func getConfigDataToWrite() (string, string, string, string, string) {
 			loader = menu.Display()
 			i = 3
 		case 3:
+			menu := cli.NewMenu("Choose release channel")
+			menu.AddItem("Release only", ChannelRelease)
+			menu.AddItem("Release and beta", ChannelBeta)
+			menu.AddItem("Everything, including alpha", ChannelAlpha)
+			channel = menu.Display()
+			i = 4
+		case 4:
 			fmt.Print("How does this profile should be called?\n")
 			_, err := fmt.Scanln(&name)
 			checkError(err)
 			if name != "" {
-				i = 4
+				i = 5
 			}
 		}
 	}
 	hash = generateRandomHash()
-	return folder, mineVersion, loader, name, hash
+	return folder, mineVersion, loader, channel, name, hash
 }
 
 func readConfig(path string) Config {
@@ -505,6 +782,22 @@ func readFullConfig(path string) MultiConfig {
 	return config
 }
 
+// function to write changes of a single profile back to the config
+func saveProfile(profile Config) {
+	configPath, _ := getConfigPath()
+	config := readFullConfig(configPath)
+	for i := range config.Profiles {
+		if config.Profiles[i].Hash == profile.Hash {
+			config.Profiles[i] = profile
+		}
+	}
+
+	jsonData, _ := json.MarshalIndent(config, "", "  ")
+
+	err := os.WriteFile(configPath, jsonData, 0644)
+	checkError(err)
+}
+
 func deleteConfig() {
 	configPath, _ := getConfigPath()
 	if !dirExists(configPath) {
@@ -521,7 +814,8 @@ func deleteConfig() {
 		}
 	}
 	selectedProfile := menu.Display()
-	var newConfig MultiConfig
+	newConfig := configData
+	newConfig.Profiles = nil
 	needToChooseNewProfile := false
 	for i := range configData.Profiles {
 		if !(selectedProfile == configData.Profiles[i].Hash) {
@@ -583,23 +877,45 @@ func generateRandomHash() string {
 	return hashString
 }
 
-func getSHA512HashesFromDirectory(dir string) []string {
-	var hashes []string
+// function to map SHA512 hashes of mod files in a directory to their file names
+func getSHA512FilesFromDirectory(dir string) map[string]string {
+	hashes := map[string]string{}
 
 	files, err := os.ReadDir(dir)
 	checkError(err)
 
 	for _, file := range files {
-		if !file.IsDir() {
+		// hidden files are partial downloads and such, not mods
+		if !file.IsDir() && file.Name() != LockFileName && !strings.HasPrefix(file.Name(), ".") {
 			filePath := path.Join(dir, file.Name())
 			hash := hashFileSHA512(filePath)
-			hashes = append(hashes, hash)
+			hashes[hash] = file.Name()
 		}
 	}
 
 	return hashes
 }
 
+// function to calculate SHA1 and SHA512 of a file in one pass
+func hashFileSums(filePath string) modrinth.Hashes {
+	file, err := os.Open(filePath)
+	checkError(err)
+	defer func(file *os.File) {
+		err := file.Close()
+		checkError(err)
+	}(file)
+
+	sha1Hash := sha1.New()
+	sha512Hash := sha512.New()
+	_, err = io.Copy(io.MultiWriter(sha1Hash, sha512Hash), file)
+	checkError(err)
+
+	return modrinth.Hashes{
+		SHA1:   hex.EncodeToString(sha1Hash.Sum(nil)),
+		SHA512: hex.EncodeToString(sha512Hash.Sum(nil)),
+	}
+}
+
 // function to calculate SHA512 from file
 func hashFileSHA512(filePath string) string {
 	file, err := os.Open(filePath)
@@ -624,7 +940,22 @@ func checkError(err error) {
 	}
 }
 
-func upgrade() {
+// parseFlags parses flags placed before or after positional arguments and returns the positional ones
+func parseFlags(flags *flag.FlagSet, args []string) []string {
+	var positional []string
+	for {
+		err := flags.Parse(args)
+		checkError(err)
+		args = flags.Args()
+		if len(args) == 0 {
+			return positional
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+func upgrade(force bool, backward []bool) {
 	configPath, _ := getConfigPath()
 	if !dirExists(configPath) {
 		fmt.Printf("%sNo profile found to upgrade%s", Red, Reset)
@@ -637,98 +968,121 @@ func upgrade() {
 	}
 
 	modsPath := configData.ModsFolder
-	loader := configData.Loader
-	gameVersion := configData.GameVersion
-
-	hashes := getSHA512HashesFromDirectory(modsPath)
 
-	if len(hashes) < 1 {
+	localFiles := getSHA512FilesFromDirectory(modsPath)
+	if len(localFiles) < 1 {
 		fmt.Println("There's no mods, type gorium add")
 		return
 	}
 
-	loaderList := []string{loader}
-
-	switch loader {
-	case "quilt":
-		loaderList = append(loaderList, "fabric")
-	case "neoforge":
-		loaderList = append(loaderList, "forge")
-	default:
+	current, err := fetchVersionsFromFiles(modsPath, localFiles)
+	if err != nil {
+		printError(err)
+		return
 	}
 
-	data := HashesToSend{
-		Hashes:    hashes,
-		Algorithm: "sha512",
-		Loaders:   loaderList,
-		GameVersions: []string{
-			gameVersion,
-		},
+	// held mods are looked up as well, only to tell that something newer exists
+	updates, heldUpdates, err := fetchUpdates(current, configData, backward)
+	if err != nil {
+		printError(err)
+		return
 	}
+	printHeldUpdates(current, heldUpdates)
+
+	// mods installed before the lockfile existed get recorded as soon as we see them
+	lock := readLockFile(modsPath)
+	for hash, version := range current {
+		if lock.findMod(version.ProjectID) == nil {
+			file := fileWithHash(&version, hash)
+			file.Filename = localFiles[hash]
+			file.Hashes.SHA512 = hash
+			lock.setMod(lockedModFromVersion(&version, file, ReasonRequested, ""))
+		}
+	}
+	writeLockFile(modsPath, lock)
 
-	jsonData, _ := json.MarshalIndent(data, "", "  ")
-
-	url := "https://api.modrinth.com/v2/version_files/update"
-	url2 := "https://api.modrinth.com/v2/version_files"
-
-	r := bytes.NewReader(jsonData)
-
-	body := sendModrinthAPIRequest(url, "POST", r, "application/json")
-	body2 := sendModrinthAPIRequest(url2, "POST", r, "application/json")
-
-	var rootMap map[string]Root
-	var rootMap2 map[string]Root
-	err := json.Unmarshal(body, &rootMap)
-	checkError(err)
-	err = json.Unmarshal(body2, &rootMap2)
-	checkError(err)
+	installed := map[string]modrinth.Version{}
+	for _, version := range current {
+		installed[version.ProjectID] = version
+	}
 
-	var fileList []map[string]string
-	var filteredRootMap []Root
-	for _, root := range rootMap {
-		isDuplicate := false
-		for _, root2 := range rootMap2 {
-			if root.DatePublished == root2.DatePublished && root.ProjectID == root2.ProjectID {
-				isDuplicate = true
-				break
-			}
-		}
-		if !isDuplicate {
-			filteredRootMap = append(filteredRootMap, root)
+	var newVersions []*modrinth.Version
+	var oldFiles []string
+	for hash, update := range updates {
+		currentVersion, ok := current[hash]
+		if ok && !isNewerVersion(update, currentVersion) {
+			continue
 		}
+		newVersions = append(newVersions, &update)
+		delete(installed, update.ProjectID)
+		oldFiles = append(oldFiles, localFiles[hash])
 	}
 
-	for _, root := range filteredRootMap {
-		for _, file := range root.Files {
-			fileInfo := map[string]string{
-				"url":      file.URL,
-				"filename": file.Filename,
-			}
-			if !strings.Contains(fileInfo["filename"], "sources") {
-				fileList = append(fileList, fileInfo)
-			}
+	if len(newVersions) == 0 {
+		fmt.Println("No updates found")
+		return
+	}
+
+	if !force {
+		conflicts := findIncompatibilities(newVersions, installed)
+		if len(conflicts) > 0 {
+			printIncompatibilities(conflicts)
+			return
 		}
 	}
 
-	for _, root2 := range rootMap {
-		for _, root3 := range rootMap2 {
-			for _, file3 := range root3.Files {
-				if root2.ProjectID == root3.ProjectID && root2.DatePublished != root3.DatePublished {
-					if dirExists(path.Join(modsPath, file3.Filename)) {
-						err := os.Remove(path.Join(modsPath, file3.Filename))
-						checkError(err)
-					}
-				}
-			}
+	var fileList []map[string]string
+	var newFiles []string
+	expectedHashes := map[string]string{}
+	for _, version := range newVersions {
+		if err := resolveDownloadURL(version); err != nil {
+			printError(err)
+			return
 		}
+		file := primaryFile(version)
+		fileList = append(fileList, map[string]string{
+			"url":      file.URL,
+			"filename": file.Filename,
+			"sha1":     file.Hashes.SHA1,
+			"sha512":   file.Hashes.SHA512,
+			"sha256":   file.Hashes.SHA256,
+		})
+		newFiles = append(newFiles, file.Filename)
+		expectedHashes[file.Filename] = file.Hashes.SHA512
 	}
 
-	if len(fileList) == 0 {
-		fmt.Println("No updates found")
+	// new files are downloaded and checked aside, the mods folder is only touched once all of them are fine
+	transaction, err := newModsTransaction(modsPath)
+	checkError(err)
+	defer transaction.cleanup()
+
+	if err := downloadFilesConcurrently(transaction.stagingDir, fileList); err != nil {
+		fmt.Printf("%sUpgrade failed, nothing was changed%s\n", Red, Reset)
+		return
+	}
+	if err := transaction.verify(expectedHashes); err != nil {
+		fmt.Printf("%sUpgrade failed, nothing was changed: %s%s\n", Red, err.Error(), Reset)
+		return
+	}
+	if err := transaction.commit(oldFiles, newFiles); err != nil {
+		fmt.Printf("%sUpgrade failed, old mods were restored: %s%s\n", Red, err.Error(), Reset)
 		return
 	}
 
-	downloadFilesConcurrently(modsPath, fileList)
+	for _, version := range newVersions {
+		fillDownloadedHash(version, modsPath)
+	}
+	rememberVersionFiles(newVersions...)
+	for _, version := range newVersions {
+		file := primaryFile(version)
+		reason, requiredBy := ReasonRequested, ""
+		if existing := lock.findMod(version.ProjectID); existing != nil {
+			reason, requiredBy = existing.Reason, existing.RequiredBy
+		}
+		lock.setMod(lockedModFromVersion(version, file, reason, requiredBy))
+	}
+	writeLockFile(modsPath, lock)
+
 	fmt.Printf("%sUpgrade completed succesfully%s", Green, Reset)
 	return
 }
@@ -786,44 +1140,52 @@ func listMods() {
 	if !dirExists(configPath) {
 		log.Fatal(Red + "No profile found, type gorium profile create" + Reset)
 	}
-	url := "https://api.modrinth.com/v2/version_files"
-
 	configData := readConfig(configPath)
 	modsFolder := configData.ModsFolder
-	gameVersion := configData.GameVersion
-	loader := configData.Loader
-	hashes := getSHA512HashesFromDirectory(modsFolder)
+	localFiles := getSHA512FilesFromDirectory(modsFolder)
 
-	if len(hashes) < 1 {
+	if len(localFiles) < 1 {
 		log.Fatal("There's no mods, type gorium add")
 	}
 
-	data := HashesToSend{
-		Hashes:    hashes,
-		Algorithm: "sha512",
-		Loaders: []string{
-			loader,
-		},
-		GameVersions: []string{
-			gameVersion,
-		},
+	versions, err := fetchVersionsFromFiles(modsFolder, localFiles)
+	if err != nil {
+		printError(err)
+		return
 	}
 
-	jsonData, _ := json.MarshalIndent(data, "", "  ")
-
-	r := bytes.NewReader(jsonData)
-
-	body := sendModrinthAPIRequest(url, "POST", r, "application/json")
-
-	var rootMap map[string]Root
-
-	err := json.Unmarshal(body, &rootMap)
-	checkError(err)
-
 	i := 1
 
-	for _, root := range rootMap {
-		fmt.Printf("[%d] %s (%s) \n", i, root.Name, root.Files[0].Filename)
+	for _, version := range versions {
+		held := ""
+		if configData.isHeld(version.ProjectID) {
+			held = fmt.Sprintf(" [%sHeld%s]", Yellow, Reset)
+		}
+		source := ""
+		if provider := providerFor(version.ProjectID); !isDefaultProvider(provider) {
+			source = fmt.Sprintf(" [%s%s%s]", Cyan, provider.Name(), Reset)
+		}
+		fmt.Printf("[%d] %s (%s)%s%s \n", i, version.Name, version.Files[0].Filename, source, held)
+		i += 1
+	}
+
+	// jars no provider knows are shown too, with where they came from if they were added with gorium add
+	lock := readLockFile(modsFolder)
+	var unknown []string
+	for hash, filename := range localFiles {
+		if _, ok := versions[hash]; ok {
+			continue
+		}
+		if mod := lock.findUnmanaged(filename); mod != nil && mod.SHA512 == hash {
+			fmt.Printf("[%d] %s [%sunmanaged%s] %sfrom %s%s\n", i, filename, Yellow, Reset, White, mod.Origin, Reset)
+			i += 1
+			continue
+		}
+		unknown = append(unknown, filename)
+	}
+	sort.Strings(unknown)
+	for _, filename := range unknown {
+		fmt.Printf("[%d] %s [%sunknown%s] %snot added with gorium, see gorium add <file>%s\n", i, filename, Red, Reset, White, Reset)
 		i += 1
 	}
 
@@ -839,7 +1201,7 @@ func contains(slice []string, str string) bool {
 	return false
 }
 
-func Search(modName string, backward []bool) {
+func Search(modName string, backward []bool, force bool) {
 	configPath, _ := getConfigPath()
 	if !dirExists(configPath) {
 		fmt.Println(Red + "No profile found, type gorium profile create" + Reset)
@@ -849,18 +1211,24 @@ func Search(modName string, backward []bool) {
 
 	loader := configData.Loader
 	version := configData.GameVersion
-	modsPath := configData.ModsFolder
 
-	urlSearch := fmt.Sprintf("https://api.modrinth.com/v2/search?query=%s&limit=100", modName)
-
-	body := sendModrinthAPIRequest(urlSearch, "GET", nil, "")
-	var results SearchRoot
-	err := json.Unmarshal(body, &results)
-	checkError(err)
+	provider, query, err := splitProvider(modName)
+	if err != nil {
+		printError(err)
+		return
+	}
+	if !isDefaultProvider(provider) {
+		query = strings.TrimPrefix(query, provider.Prefix()+":")
+	}
+	hits, err := provider.Search(query, version)
+	if err != nil {
+		printError(err)
+		return
+	}
 
-	var sortedResults SearchRoot
+	var sortedResults modrinth.SearchResult
 
-	for _, hit := range results.Hits {
+	for _, hit := range hits {
 		if hit.ProjectType == "mod" && contains(hit.Versions, version) {
 			if backward[1] {
 				if contains(hit.Categories, loader) || contains(hit.Categories, "forge") {
@@ -908,25 +1276,16 @@ func Search(modName string, backward []bool) {
 		modsToDownload = append(modsToDownload, sortedResults.Hits[selectedIntegers[i]].ProjectID)
 	}
 
-	type Versions struct {
-		Version []*Version
-	}
-	var latestVersions Versions
+	var latestVersions []*modrinth.Version
 	for i := range modsToDownload {
-		latestVersions.Version = append(latestVersions.Version, fetchLatestVersion(modsToDownload[i], version, loader, backward))
-	}
-
-	var filesToDownload []map[string]string
-	for _, root := range latestVersions.Version {
-		for _, file := range root.Files {
-			fileInfo := map[string]string{
-				"url":      file.URL,
-				"filename": file.Filename,
-			}
-			if !strings.Contains(fileInfo["filename"], "sources") {
-				filesToDownload = append(filesToDownload, fileInfo)
-			}
+		latestVersion := fetchLatestVersion(modsToDownload[i], configData, backward)
+		if latestVersion != nil {
+			latestVersions = append(latestVersions, latestVersion)
 		}
 	}
-	downloadFilesConcurrently(modsPath, filesToDownload)
+	if len(latestVersions) == 0 {
+		return
+	}
+
+	installVersions(latestVersions, configData, backward, force)
 }
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+	"strings"
+
+	"gorium/modrinth"
+)
+
+// Version and project metadata is kept next to the response cache, so lookups by hash and by
+// project ID can be answered offline. Hash lookups are POST requests the response cache can't keep.
+
+func getMetadataDir(kind string) string {
+	return path.Join(getCacheDir(), "metadata", kind)
+}
+
+// function to make a file name out of an ID, IDs of other providers than Modrinth have a prefix like "cf:"
+// and may contain slashes
+func metadataName(id string) string {
+	return strings.NewReplacer(":", "_", "/", "_", "\\", "_").Replace(id)
+}
+
+// function to write a value as JSON through a temporary file, errors are ignored since it's only a cache
+func writeMetadata(dir string, name string, value any) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+	temp, err := os.CreateTemp(dir, ".metadata-*")
+	if err != nil {
+		return
+	}
+	_, err = temp.Write(data)
+	if closeErr := temp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil || os.Rename(temp.Name(), path.Join(dir, metadataName(name)+".json")) != nil {
+		_ = os.Remove(temp.Name())
+	}
+}
+
+func readMetadata(dir string, name string, value any) bool {
+	data, err := os.ReadFile(path.Join(dir, metadataName(name)+".json"))
+	return err == nil && json.Unmarshal(data, value) == nil
+}
+
+// function to remember which version the files with the given SHA512 hashes belong to
+func rememberVersions(versions map[string]modrinth.Version) {
+	dir := getMetadataDir("versions")
+	for hash, version := range versions {
+		writeMetadata(dir, hash, version)
+	}
+}
+
+// function to remember the version of every file of the given versions
+func rememberVersionFiles(versions ...*modrinth.Version) {
+	byHash := map[string]modrinth.Version{}
+	for _, version := range versions {
+		for _, file := range version.Files {
+			if file.Hashes.SHA512 != "" {
+				byHash[file.Hashes.SHA512] = *version
+			}
+		}
+	}
+	rememberVersions(byHash)
+}
+
+// function to look up versions by SHA512 hashes in the metadata cache, unknown hashes are left out
+func cachedVersionsFromHashes(hashes []string) map[string]modrinth.Version {
+	dir := getMetadataDir("versions")
+	versions := map[string]modrinth.Version{}
+	for _, hash := range hashes {
+		var version modrinth.Version
+		if readMetadata(dir, hash, &version) {
+			versions[hash] = version
+		}
+	}
+	return versions
+}
+
+func rememberProjects(projects ...modrinth.Project) {
+	dir := getMetadataDir("projects")
+	for _, project := range projects {
+		writeMetadata(dir, project.ID, project)
+	}
+}
+
+// function to look up projects by ID in the metadata cache, unknown ones are left out
+func cachedProjects(projectIDs []string) map[string]modrinth.Project {
+	dir := getMetadataDir("projects")
+	projects := map[string]modrinth.Project{}
+	for _, projectID := range projectIDs {
+		var project modrinth.Project
+		if readMetadata(dir, projectID, &project) {
+			projects[project.ID] = project
+		}
+	}
+	return projects
+}
+
+// function to find a project in the metadata cache by its ID or slug
+func cachedProject(slugOrID string) *modrinth.Project {
+	if projects := cachedProjects([]string{slugOrID}); len(projects) > 0 {
+		project := projects[slugOrID]
+		return &project
+	}
+
+	dir := getMetadataDir("projects")
+	entries, _ := os.ReadDir(dir)
+	for _, entry := range entries {
+		var project modrinth.Project
+		if readMetadata(dir, strings.TrimSuffix(entry.Name(), ".json"), &project) && strings.EqualFold(project.Slug, slugOrID) {
+			return &project
+		}
+	}
+	return nil
+}
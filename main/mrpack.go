@@ -0,0 +1,80 @@
+package main
+
+import (
+	"net/url"
+	"slices"
+	"strings"
+
+	"gorium/modrinth"
+)
+
+// Modrinth modpacks (.mrpack) are zip files with a modrinth.index.json listing files to download and
+// an overrides/ folder of files copied as they are. The format is described at
+// https://support.modrinth.com/en/articles/8802351-modrinth-modpack-format-mrpack
+
+const (
+	mrpackIndexName     = "modrinth.index.json"
+	mrpackFormatVersion = 1
+	mrpackOverrides     = "overrides"
+)
+
+// MrpackIndex is the modrinth.index.json of a modpack
+type MrpackIndex struct {
+	FormatVersion int          `json:"formatVersion"`
+	Game          string       `json:"game"`
+	VersionID     string       `json:"versionId"`
+	Name          string       `json:"name"`
+	Summary       string       `json:"summary,omitempty"`
+	Files         []MrpackFile `json:"files"`
+	// Dependencies are the versions of minecraft and the loader, like "fabric-loader": "0.16.5"
+	Dependencies map[string]string `json:"dependencies"`
+}
+
+type MrpackFile struct {
+	// Path is where the file goes, relative to the game folder, like "mods/sodium.jar"
+	Path      string          `json:"path"`
+	Hashes    modrinth.Hashes `json:"hashes"`
+	Env       *MrpackEnv      `json:"env,omitempty"`
+	Downloads []string        `json:"downloads"`
+	FileSize  int64           `json:"fileSize"`
+}
+
+// MrpackEnv tells if a file is needed on the client and the server: required, optional or unsupported
+type MrpackEnv struct {
+	Client string `json:"client"`
+	Server string `json:"server"`
+}
+
+// names of the loaders in the dependencies of a modpack
+var mrpackLoaders = map[string]string{
+	"fabric":   "fabric-loader",
+	"quilt":    "quilt-loader",
+	"forge":    "forge",
+	"neoforge": "neoforge",
+}
+
+// loaders in the order a modpack that depends on several is installed with. Quilt runs Fabric mods,
+// so a pack naming both needs Quilt.
+var mrpackLoaderPriority = []string{"quilt", "fabric", "neoforge", "forge"}
+
+// launchers only download files from these hosts, others have to go into overrides/
+var mrpackDownloadHosts = []string{"cdn.modrinth.com", "github.com", "raw.githubusercontent.com", "gitlab.com"}
+
+// function to check if launchers will download a modpack file from an address
+func mrpackDownloadAllowed(address string) bool {
+	parsed, err := url.Parse(address)
+	if err != nil || parsed.Scheme != "https" {
+		return false
+	}
+	return slices.Contains(mrpackDownloadHosts, strings.ToLower(parsed.Hostname()))
+}
+
+// function to turn the client or server side of a project into a modpack env value, Modrinth also has "unknown"
+func mrpackSide(side string) string {
+	switch side {
+	case "optional", "unsupported":
+		return side
+	default:
+		return "required"
+	}
+}
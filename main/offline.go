@@ -0,0 +1,61 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sync"
+
+	"gorium/modrinth"
+)
+
+var offlineNotice sync.Once
+
+// goOffline makes gorium answer from cached data only. automatic is true when Modrinth couldn't be
+// reached, the user is told about it once.
+func goOffline(automatic bool) {
+	responseCache.SetOffline(true)
+	curseforgeCache.SetOffline(true)
+	githubCache.SetOffline(true)
+	if automatic {
+		offlineNotice.Do(func() {
+			fmt.Printf("%sModrinth can't be reached, using cached data%s\n", Yellow, Reset)
+		})
+	}
+}
+
+func isOffline() bool {
+	return responseCache.IsOffline()
+}
+
+// function to check if a request failed because Modrinth couldn't be reached at all
+func isNetworkError(err error) bool {
+	var apiErr *modrinth.APIError
+	if errors.As(err, &apiErr) {
+		return false
+	}
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
+
+// function to tell the user that a command can't run offline, returns true if it can go on
+func requireOnline(command string) bool {
+	if !isOffline() {
+		return true
+	}
+	fmt.Printf("%sgorium %s needs Modrinth and can't run with --offline%s\n", Red, command, Reset)
+	return false
+}
+
+// function to remove the --offline flag from the arguments, it works with every command
+func parseOfflineFlag(args []string) []string {
+	var rest []string
+	for _, arg := range args {
+		if arg == "--offline" || arg == "-offline" {
+			goOffline(false)
+			continue
+		}
+		rest = append(rest, arg)
+	}
+	return rest
+}
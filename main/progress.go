@@ -0,0 +1,300 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// how often the live progress display is redrawn
+const progressRefresh = 200 * time.Millisecond
+
+// DownloadProgress tracks a batch of downloads. On a terminal it keeps a live block with one line
+// per active download and a total line at the bottom, otherwise it prints plain lines as things happen.
+type DownloadProgress struct {
+	mutex      sync.Mutex
+	files      []*FileProgress
+	totalFiles int
+	finished   int
+	started    time.Time
+	live       bool
+	linesDrawn int
+	stop       chan struct{}
+	stopped    chan struct{}
+}
+
+// FileProgress tracks a single download of a batch
+type FileProgress struct {
+	batch   *DownloadProgress
+	name    string
+	size    int64 // -1 while unknown
+	done    int64
+	resumed int64 // bytes that were already on disk, they don't count towards the speed
+	started time.Time
+	active  bool
+	cached  string // how the file was taken from the jar cache instead of downloaded, empty if it wasn't
+}
+
+// function to start tracking a batch of downloads
+func newDownloadProgress(totalFiles int) *DownloadProgress {
+	progress := &DownloadProgress{
+		totalFiles: totalFiles,
+		started:    time.Now(),
+		live:       term.IsTerminal(int(os.Stdout.Fd())),
+		stop:       make(chan struct{}),
+		stopped:    make(chan struct{}),
+	}
+
+	if !progress.live {
+		close(progress.stopped)
+		return progress
+	}
+
+	go func() {
+		defer close(progress.stopped)
+		ticker := time.NewTicker(progressRefresh)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				progress.mutex.Lock()
+				progress.redraw()
+				progress.mutex.Unlock()
+			case <-progress.stop:
+				return
+			}
+		}
+	}()
+	return progress
+}
+
+// function to register a file of the batch, it shows up once start is called
+func (progress *DownloadProgress) addFile(name string) *FileProgress {
+	file := &FileProgress{batch: progress, name: name, size: -1}
+
+	progress.mutex.Lock()
+	defer progress.mutex.Unlock()
+	progress.files = append(progress.files, file)
+	return file
+}
+
+// finish stops the live display and prints the final total line
+func (progress *DownloadProgress) finish() {
+	if progress.live {
+		close(progress.stop)
+	}
+	<-progress.stopped
+
+	progress.mutex.Lock()
+	defer progress.mutex.Unlock()
+	progress.clear()
+	if progress.totalFiles > 0 {
+		fmt.Println(progress.totalLine())
+	}
+}
+
+// function to erase the live block, the caller has to hold the mutex
+func (progress *DownloadProgress) clear() {
+	if !progress.live || progress.linesDrawn == 0 {
+		return
+	}
+	fmt.Printf("\033[%dA", progress.linesDrawn)
+	for i := 0; i < progress.linesDrawn; i++ {
+		fmt.Print("\033[2K\n")
+	}
+	fmt.Printf("\033[%dA", progress.linesDrawn)
+	progress.linesDrawn = 0
+}
+
+// function to draw the live block again, the caller has to hold the mutex
+func (progress *DownloadProgress) redraw() {
+	if !progress.live {
+		return
+	}
+	progress.clear()
+
+	width, _, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil || width < 20 {
+		width = 80
+	}
+
+	var lines []string
+	for _, file := range progress.files {
+		if file.active {
+			lines = append(lines, file.line(width))
+		}
+	}
+	lines = append(lines, progress.totalLine())
+
+	for _, line := range lines {
+		fmt.Printf("\033[2K%s\n", line)
+	}
+	progress.linesDrawn = len(lines)
+}
+
+// function to make the line with the progress of the whole batch, the caller has to hold the mutex
+func (progress *DownloadProgress) totalLine() string {
+	var done, resumed, size int64
+	sizeKnown := true
+	for _, file := range progress.files {
+		done += file.done
+		resumed += file.resumed
+		if file.size < 0 {
+			sizeKnown = false
+		}
+		size += file.size
+	}
+
+	elapsed := time.Since(progress.started)
+	line := fmt.Sprintf("%s[Total]%s %d/%d files, %s", Bold, Reset, progress.finished, progress.totalFiles, formatBytes(done))
+	if sizeKnown && len(progress.files) == progress.totalFiles {
+		line += " of " + formatBytes(size)
+	}
+	line += ", " + formatSpeed(done-resumed, elapsed)
+	if sizeKnown && len(progress.files) == progress.totalFiles && progress.finished < progress.totalFiles {
+		line += ", ETA " + formatETA(size-done, done-resumed, elapsed)
+	}
+	return line
+}
+
+// function to make the line of a single download, the caller has to hold the batch mutex
+func (file *FileProgress) line(width int) string {
+	elapsed := time.Since(file.started)
+
+	stats := " " + formatBytes(file.done)
+	if file.size > 0 {
+		stats += "/" + formatBytes(file.size)
+	}
+	stats += " " + formatSpeed(file.done-file.resumed, elapsed)
+	if file.size > 0 {
+		stats += " ETA " + formatETA(file.size-file.done, file.done-file.resumed, elapsed)
+	}
+
+	name := file.name
+	barWidth := 20
+	if maxName := width - len(stats) - barWidth - 4; len(name) > maxName && maxName > 3 {
+		name = name[:maxName-3] + "..."
+	}
+
+	bar := strings.Repeat(" ", barWidth)
+	if file.size > 0 {
+		filled := int(file.done * int64(barWidth) / file.size)
+		bar = strings.Repeat("=", filled) + strings.Repeat(" ", barWidth-filled)
+	}
+	return fmt.Sprintf("%s%s%s [%s]%s", Cyan, name, Reset, bar, stats)
+}
+
+// start marks the download as running. size is -1 if the server didn't say, resumed is what's already on disk.
+func (file *FileProgress) start(size int64, resumed int64) {
+	batch := file.batch
+	batch.mutex.Lock()
+	defer batch.mutex.Unlock()
+
+	first := !file.active && file.started.IsZero()
+	file.active = true
+	file.started = time.Now()
+	file.done = resumed
+	file.resumed = resumed
+	file.size = size
+	if size >= 0 {
+		file.size = size + resumed
+	}
+
+	if first && !batch.live {
+		fmt.Printf("[Downloading] [%s%s%s]\n", Cyan, file.name, Reset)
+	}
+}
+
+// fromCache marks the file as taken from the jar cache, it counts as done right away
+func (file *FileProgress) fromCache(filePath string, method string) {
+	var size int64
+	if info, err := os.Stat(filePath); err == nil {
+		size = info.Size()
+	}
+
+	batch := file.batch
+	batch.mutex.Lock()
+	defer batch.mutex.Unlock()
+	file.cached = method
+	file.started = time.Now()
+	file.size = size
+	file.done = size
+	file.resumed = size
+}
+
+// end marks the download as finished and prints a line about it
+func (file *FileProgress) end(err error) {
+	batch := file.batch
+	batch.mutex.Lock()
+	defer batch.mutex.Unlock()
+
+	file.active = false
+	batch.finished++
+	if file.size < 0 {
+		file.size = file.done
+	}
+
+	batch.clear()
+	if err != nil {
+		fmt.Printf("%sError: %s%s\n", Red, err.Error(), Reset)
+	} else if file.cached != "" {
+		fmt.Printf("[%sFrom cache%s] [%s%s%s] %s, %s\n", Green, Reset, Cyan, file.name, Reset, formatBytes(file.done), file.cached)
+	} else {
+		fmt.Printf("[%sDownloaded%s] [%s%s%s] %s\n", Green, Reset, Cyan, file.name, Reset, formatBytes(file.done))
+	}
+	batch.redraw()
+}
+
+// reader wraps the response body so every read counts towards the progress
+func (file *FileProgress) reader(body io.Reader) io.Reader {
+	return &progressReader{reader: body, file: file}
+}
+
+type progressReader struct {
+	reader io.Reader
+	file   *FileProgress
+}
+
+func (reader *progressReader) Read(buffer []byte) (int, error) {
+	n, err := reader.reader.Read(buffer)
+	if n > 0 {
+		batch := reader.file.batch
+		batch.mutex.Lock()
+		reader.file.done += int64(n)
+		batch.mutex.Unlock()
+	}
+	return n, err
+}
+
+func formatBytes(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
+
+func formatSpeed(bytes int64, elapsed time.Duration) string {
+	if elapsed <= 0 {
+		return "0 B/s"
+	}
+	return formatBytes(int64(float64(bytes)/elapsed.Seconds())) + "/s"
+}
+
+func formatETA(left int64, done int64, elapsed time.Duration) string {
+	if done <= 0 || left <= 0 {
+		return "-"
+	}
+	eta := time.Duration(float64(elapsed) * float64(left) / float64(done))
+	return eta.Round(time.Second).String()
+}
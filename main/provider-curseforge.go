@@ -0,0 +1,353 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gorium/curseforge"
+	"gorium/modrinth"
+)
+
+// environment variable that overrides the CurseForge API address, for the fake server
+const curseforgeAPIEnv = "GORIUM_CURSEFORGE_API"
+
+// ErrNoCurseForgeKey is returned when a CurseForge mod is asked for without an API key in the config
+var ErrNoCurseForgeKey = errors.New(`CurseForge needs an API key, set "curseforgekey" in the config`)
+
+// curseforgeCache keeps CurseForge responses next to Modrinth's, it has its own OnStale
+// since CurseForge being down doesn't mean Modrinth is
+var curseforgeCache = &modrinth.Cache{Dir: responseCache.Dir, TTL: responseCache.TTL}
+
+var curseforgeClient = newCurseForgeClient()
+
+func newCurseForgeClient() *curseforge.Client {
+	settings := readGlobalSettings()
+	client := curseforge.NewClient(FullVersion, settings.CurseForgeKey)
+	client.BaseURL = curseforge.DefaultBaseURL
+	if api := os.Getenv(curseforgeAPIEnv); api != "" {
+		client.BaseURL = api
+	} else if settings.CurseForgeAPI != "" {
+		client.BaseURL = settings.CurseForgeAPI
+	}
+	client.HTTPClient = &http.Client{
+		Timeout:   curseforge.DefaultTimeout,
+		Transport: curseforgeCache,
+	}
+	return client
+}
+
+// curseforgeProvider gets mods from CurseForge. Its project IDs are "cf:" and the mod ID,
+// version IDs are "cf:" and the file ID.
+type curseforgeProvider struct{}
+
+func (curseforgeProvider) Name() string {
+	return "curseforge"
+}
+
+func (curseforgeProvider) Prefix() string {
+	return "cf"
+}
+
+// function to get the number of a CurseForge ID like "cf:238222"
+func parseCurseForgeID(id string) (int, error) {
+	number, err := strconv.Atoi(strings.TrimPrefix(id, "cf:"))
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", ErrModNotFound, id)
+	}
+	return number, nil
+}
+
+func curseforgeID(number int) string {
+	return "cf:" + strconv.Itoa(number)
+}
+
+// function to give errors of the CurseForge client the provider's name and turn 404s into ErrModNotFound
+func curseforgeError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if curseforge.IsNotFound(err) {
+		err = fmt.Errorf("%w: %w", ErrModNotFound, err)
+	}
+	return &ProviderError{Provider: "CurseForge", Err: err}
+}
+
+func (provider curseforgeProvider) checkKey() error {
+	if curseforgeClient.APIKey == "" {
+		return ErrNoCurseForgeKey
+	}
+	return nil
+}
+
+func (provider curseforgeProvider) Search(query string, gameVersion string) ([]modrinth.SearchHit, error) {
+	if err := provider.checkKey(); err != nil {
+		return nil, err
+	}
+	mods, err := curseforgeClient.SearchMods(context.Background(), curseforge.SearchParams{
+		SearchFilter: query,
+		GameVersion:  gameVersion,
+		SortField:    curseforge.SortPopularity,
+		PageSize:     50,
+	})
+	if err != nil {
+		return nil, curseforgeError(err)
+	}
+
+	var hits []modrinth.SearchHit
+	for _, mod := range mods {
+		project := curseforgeProject(mod)
+		hits = append(hits, modrinth.SearchHit{
+			ProjectID:    project.ID,
+			Slug:         project.Slug,
+			Title:        project.Title,
+			Description:  project.Description,
+			Categories:   project.Loaders,
+			ProjectType:  project.ProjectType,
+			Downloads:    project.Downloads,
+			Versions:     project.GameVersions,
+			DateCreated:  project.Published,
+			DateModified: project.Updated,
+		})
+	}
+	return hits, nil
+}
+
+// Project accepts a mod ID, with or without the "cf:" prefix, or a slug
+func (provider curseforgeProvider) Project(slugOrID string) (*modrinth.Project, error) {
+	if err := provider.checkKey(); err != nil {
+		return nil, err
+	}
+	name := strings.TrimPrefix(slugOrID, "cf:")
+
+	var mod *curseforge.Mod
+	var err error
+	if modID, convErr := strconv.Atoi(name); convErr == nil {
+		mod, err = curseforgeClient.GetMod(context.Background(), modID)
+	} else {
+		mod, err = curseforgeClient.GetModBySlug(context.Background(), name)
+		if err == nil && mod == nil {
+			err = fmt.Errorf("%w: %s", ErrModNotFound, slugOrID)
+		}
+	}
+	if err != nil {
+		return nil, curseforgeError(err)
+	}
+	project := curseforgeProject(*mod)
+	return &project, nil
+}
+
+func (provider curseforgeProvider) Projects(projectIDs []string) ([]modrinth.Project, error) {
+	if err := provider.checkKey(); err != nil {
+		return nil, err
+	}
+	var modIDs []int
+	for _, projectID := range projectIDs {
+		if modID, err := parseCurseForgeID(projectID); err == nil {
+			modIDs = append(modIDs, modID)
+		}
+	}
+	mods, err := curseforgeClient.GetMods(context.Background(), modIDs)
+	if err != nil {
+		return nil, curseforgeError(err)
+	}
+
+	var projects []modrinth.Project
+	for _, mod := range mods {
+		projects = append(projects, curseforgeProject(mod))
+	}
+	return projects, nil
+}
+
+func (provider curseforgeProvider) Versions(slugOrID string, gameVersion string) ([]modrinth.Version, error) {
+	project, err := provider.Project(slugOrID)
+	if err != nil {
+		return nil, err
+	}
+	modID, err := parseCurseForgeID(project.ID)
+	if err != nil {
+		return nil, err
+	}
+	files, err := curseforgeClient.GetModFiles(context.Background(), modID, &curseforge.FileFilter{GameVersion: gameVersion})
+	if err != nil {
+		return nil, curseforgeError(err)
+	}
+
+	var versions []modrinth.Version
+	for _, file := range files {
+		versions = append(versions, curseforgeVersion(file))
+	}
+	return versions, nil
+}
+
+func (provider curseforgeProvider) Version(versionID string) (*modrinth.Version, error) {
+	if err := provider.checkKey(); err != nil {
+		return nil, err
+	}
+	fileID, err := parseCurseForgeID(versionID)
+	if err != nil {
+		return nil, err
+	}
+	files, err := curseforgeClient.GetFiles(context.Background(), []int{fileID})
+	if err != nil {
+		return nil, curseforgeError(err)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrModNotFound, versionID)
+	}
+	version := curseforgeVersion(files[0])
+	return &version, nil
+}
+
+// LookupFiles finds files by their CurseForge fingerprint. Without an API key nothing is found,
+// so profiles that only use Modrinth don't need one.
+func (provider curseforgeProvider) LookupFiles(files map[string]string) (map[string]modrinth.Version, error) {
+	versions := map[string]modrinth.Version{}
+	if provider.checkKey() != nil {
+		return versions, nil
+	}
+
+	hashes := map[uint32]string{}
+	var fingerprints []uint32
+	for hash, filePath := range files {
+		fingerprint, err := curseforge.FingerprintFile(filePath)
+		if err != nil {
+			return nil, err
+		}
+		hashes[fingerprint] = hash
+		fingerprints = append(fingerprints, fingerprint)
+	}
+
+	result, err := curseforgeClient.MatchFingerprints(context.Background(), fingerprints)
+	if err != nil {
+		return nil, curseforgeError(err)
+	}
+	for _, match := range result.ExactMatches {
+		hash, ok := hashes[match.File.FileFingerprint]
+		if !ok {
+			continue
+		}
+		version := curseforgeVersion(match.File)
+		// CurseForge has no SHA512 hashes, but we know this one
+		version.Files[0].Hashes.SHA512 = hash
+		versions[hash] = version
+	}
+	return versions, nil
+}
+
+// DownloadURL asks CurseForge for the address if the file didn't come with one. Authors can keep
+// other apps from downloading their mods, those have to be downloaded from the website.
+func (provider curseforgeProvider) DownloadURL(version *modrinth.Version, file modrinth.File) (string, error) {
+	if file.URL != "" {
+		return file.URL, nil
+	}
+	modID, err := parseCurseForgeID(version.ProjectID)
+	if err != nil {
+		return "", err
+	}
+	fileID, err := parseCurseForgeID(version.ID)
+	if err != nil {
+		return "", err
+	}
+
+	// a rejected key is an error like any other, only an empty address means the author keeps the file to the website
+	downloadURL, err := curseforgeClient.GetDownloadURL(context.Background(), modID, fileID)
+	if err != nil {
+		return "", curseforgeError(err)
+	}
+	if downloadURL == "" {
+		return "", fmt.Errorf("the author of %s doesn't allow downloads outside of CurseForge, get it from the website", file.Filename)
+	}
+	return downloadURL, nil
+}
+
+func (curseforgeProvider) ProjectURL(project *modrinth.Project) string {
+	return "https://www.curseforge.com/minecraft/mc-mods/" + project.Slug
+}
+
+// function to describe a CurseForge mod as a project
+func curseforgeProject(mod curseforge.Mod) modrinth.Project {
+	project := modrinth.Project{
+		ID:          curseforgeID(mod.ID),
+		Slug:        mod.Slug,
+		Title:       mod.Name,
+		Description: mod.Summary,
+		ProjectType: "mod",
+		Downloads:   int(mod.DownloadCount),
+		IssuesURL:   mod.Links.IssuesURL,
+		SourceURL:   mod.Links.SourceURL,
+		WikiURL:     mod.Links.WikiURL,
+		Published:   mod.DateCreated,
+		Updated:     mod.DateModified,
+	}
+	for _, category := range mod.Categories {
+		project.Categories = append(project.Categories, category.Slug)
+	}
+	if mod.Logo != nil {
+		project.IconURL = mod.Logo.ThumbnailURL
+	}
+	for _, index := range mod.LatestFilesIndexes {
+		if !slices.Contains(project.GameVersions, index.GameVersion) {
+			project.GameVersions = append(project.GameVersions, index.GameVersion)
+		}
+		if loader := index.ModLoader.String(); loader != "" && !slices.Contains(project.Loaders, loader) {
+			project.Loaders = append(project.Loaders, loader)
+		}
+	}
+	return project
+}
+
+// function to describe a CurseForge file as a version with a single file
+func curseforgeVersion(file curseforge.File) modrinth.Version {
+	version := modrinth.Version{
+		ID:            curseforgeID(file.ID),
+		ProjectID:     curseforgeID(file.ModID),
+		Name:          file.DisplayName,
+		VersionNumber: strings.TrimSuffix(file.DisplayName, ".jar"),
+		GameVersions:  file.MinecraftVersions(),
+		Loaders:       file.Loaders(),
+		DatePublished: file.FileDate,
+		Downloads:     int(file.DownloadCount),
+		Status:        "listed",
+		Files: []modrinth.File{{
+			Hashes:   modrinth.Hashes{SHA1: file.SHA1()},
+			URL:      file.DownloadURL,
+			Filename: file.FileName,
+			Primary:  true,
+			Size:     file.FileLength,
+		}},
+	}
+
+	switch file.ReleaseType {
+	case curseforge.ReleaseTypeBeta:
+		version.VersionType = modrinth.VersionTypeBeta
+	case curseforge.ReleaseTypeAlpha:
+		version.VersionType = modrinth.VersionTypeAlpha
+	default:
+		version.VersionType = modrinth.VersionTypeRelease
+	}
+
+	for _, dependency := range file.Dependencies {
+		var dependencyType string
+		switch dependency.RelationType {
+		case curseforge.RelationRequiredDependency:
+			dependencyType = modrinth.DependencyRequired
+		case curseforge.RelationOptionalDependency, curseforge.RelationTool:
+			dependencyType = modrinth.DependencyOptional
+		case curseforge.RelationIncompatible:
+			dependencyType = modrinth.DependencyIncompatible
+		default:
+			dependencyType = modrinth.DependencyEmbedded
+		}
+		version.Dependencies = append(version.Dependencies, modrinth.Dependency{
+			ProjectID:      curseforgeID(dependency.ModID),
+			DependencyType: dependencyType,
+		})
+	}
+	return version
+}
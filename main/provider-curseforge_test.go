@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"gorium/curseforge/curseforgetest"
+	"gorium/modrinth"
+)
+
+const curseforgeFixtures = "../curseforge/curseforgetest/testdata/basic"
+
+// the key the fake CurseForge server accepts
+const testCurseForgeKey = "test-key"
+
+// useCurseForge starts a fake CurseForge server and puts key in the config as "curseforgekey"
+func useCurseForge(t *testing.T, key string) {
+	t.Helper()
+	fixtures, err := curseforgetest.LoadFixtures(curseforgeFixtures)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fixtures.APIKey = testCurseForgeKey
+	server := curseforgetest.NewServer(fixtures)
+	t.Cleanup(server.Close)
+	t.Setenv(curseforgeAPIEnv, server.URL)
+
+	configPath, _ := getConfigPath()
+	config := readFullConfig(configPath)
+	config.CurseForgeKey = key
+	jsonData, _ := json.MarshalIndent(config, "", "  ")
+	if err := os.WriteFile(configPath, jsonData, 0644); err != nil {
+		t.Fatal(err)
+	}
+	// responses cached with another key would hide how the server answers this one
+	curseforgeCache = &modrinth.Cache{Dir: t.TempDir(), TTL: responseCache.TTL}
+	curseforgeClient = newCurseForgeClient()
+}
+
+func TestAddFromCurseForge(t *testing.T) {
+	modsPath := newTestProfile(t, "")
+	useCurseForge(t, testCurseForgeKey)
+
+	runGorium(t, "add", "cf:jei")
+
+	expectJars(t, modsPath, "jei-1.21.1-fabric-19.20.0.1.jar")
+	expectLocked(t, modsPath, "cf:238222", "cf:5000004", ReasonRequested)
+}
+
+func TestCurseForgeRejectedKeyDoesntBlockOtherMods(t *testing.T) {
+	modsPath := newTestProfile(t, "")
+	useCurseForge(t, testCurseForgeKey)
+	runGorium(t, "add", "cf:jei")
+	runGorium(t, "add", "sodium")
+
+	useCurseForge(t, "expired-key")
+	installed, err := getInstalledMods(modsPath)
+	if err != nil {
+		t.Fatalf("a rejected CurseForge key failed the lookup: %v", err)
+	}
+	if _, ok := installed["AANobbMI"]; !ok {
+		t.Fatalf("sodium from Modrinth wasn't found, got %v", installed)
+	}
+}
+
+func TestCurseForgeDownloadURL(t *testing.T) {
+	newTestProfile(t, "")
+	useCurseForge(t, testCurseForgeKey)
+
+	provider := curseforgeProvider{}
+	versions, err := provider.Versions("cf:locked-down", "1.21.1")
+	if err != nil || len(versions) != 1 {
+		t.Fatalf("Versions gave %v, %v", versions, err)
+	}
+	locked := versions[0]
+
+	_, err = provider.DownloadURL(&locked, locked.Files[0])
+	if err == nil || !strings.Contains(err.Error(), "doesn't allow downloads") {
+		t.Fatalf("a mod the author keeps to CurseForge gave %v", err)
+	}
+
+	// a key that stopped working is a problem with the key, not with the mod
+	useCurseForge(t, "expired-key")
+	_, err = provider.DownloadURL(&locked, locked.Files[0])
+	if err == nil || !strings.Contains(errorMessage(err), "API key") {
+		t.Fatalf("a rejected key gave %v", err)
+	}
+}
@@ -0,0 +1,529 @@
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorium/github"
+	"gorium/modrinth"
+)
+
+// environment variable that overrides the GitHub API address, for GitHub Enterprise and the fake server
+const githubAPIEnv = "GORIUM_GITHUB_API"
+
+// assets matching this are used if a source has no pattern of its own
+const defaultAssetPattern = "*.jar"
+
+// jars published next to the mod that aren't meant to be installed
+var excludedAssetSuffixes = []string{"-sources.jar", "-javadoc.jar", "-dev.jar", "-api.jar"}
+
+// how many of the newest releases get their jars downloaded to look at their metadata when the
+// asset names don't tell the game version or loader. Older assets that don't say are left out.
+const inspectedReleases = 5
+
+// ErrNoSearch is returned when searching a provider that can't be searched
+var ErrNoSearch = errors.New("GitHub can't be searched, add mods from it with gorium add gh:owner/repo")
+
+// GitHubSource is a repository whose release assets are installed as a mod
+type GitHubSource struct {
+	Repo string `json:"repo"` // like "owner/repo"
+	// Pattern picks the assets to install, like "*-fabric-*.jar". It's matched with path.Match.
+	Pattern string `json:"pattern,omitempty"`
+}
+
+// githubCache keeps GitHub responses next to Modrinth's, the API allows only 60 requests an hour without a token
+var githubCache = &modrinth.Cache{Dir: responseCache.Dir, TTL: responseCache.TTL}
+
+var githubClient = newGitHubClient()
+
+// assets are downloaded from github.com, not the API, so they get a client without the response cache
+var githubDownloadClient = &http.Client{Timeout: 2 * time.Minute}
+
+// githubAssetPatterns are patterns given with gorium add --asset, they win over the ones in the profile
+var githubAssetPatterns = map[string]string{}
+
+func newGitHubClient() *github.Client {
+	settings := readGlobalSettings()
+	client := github.NewClient(FullVersion)
+	if api := os.Getenv(githubAPIEnv); api != "" {
+		client.BaseURL = api
+	} else if settings.GitHubAPI != "" {
+		client.BaseURL = settings.GitHubAPI
+	}
+	client.HTTPClient = &http.Client{
+		Timeout:   github.DefaultTimeout,
+		Transport: githubCache,
+	}
+	return client
+}
+
+// githubProvider gets mods from release assets on GitHub. Its project IDs are "gh:" and the repository
+// like "gh:owner/repo", version IDs add the tag and asset name like "gh:owner/repo@v1.0/mod-1.0.jar".
+type githubProvider struct{}
+
+func (githubProvider) Name() string {
+	return "github"
+}
+
+func (githubProvider) Prefix() string {
+	return "gh"
+}
+
+// function to get the owner and name of a repository from a name like "gh:owner/repo"
+func parseGitHubRepo(name string) (string, string, error) {
+	owner, repo, found := strings.Cut(strings.TrimPrefix(name, "gh:"), "/")
+	if !found || owner == "" || repo == "" || strings.Contains(repo, "/") {
+		return "", "", fmt.Errorf("%s isn't a GitHub repository, write it like gh:owner/repo", name)
+	}
+	return owner, repo, nil
+}
+
+// function to split a version ID like "gh:owner/repo@v1.0/mod-1.0.jar" into repository, tag and asset name
+func parseGitHubVersionID(versionID string) (string, string, string, error) {
+	repo, rest, found := strings.Cut(strings.TrimPrefix(versionID, "gh:"), "@")
+	slash := strings.LastIndex(rest, "/")
+	if !found || slash < 0 {
+		return "", "", "", fmt.Errorf("%w: %s", ErrModNotFound, versionID)
+	}
+	return repo, rest[:slash], rest[slash+1:], nil
+}
+
+// function to give errors of the GitHub client the provider's name and turn 404s into ErrModNotFound
+func githubError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if github.IsNotFound(err) {
+		err = fmt.Errorf("%w: %w", ErrModNotFound, err)
+	}
+	return &ProviderError{Provider: "GitHub", Err: err}
+}
+
+// function to read the active profile, an empty one if there's no config
+func activeProfile() Config {
+	configPath, _ := getConfigPath()
+	if !dirExists(configPath) {
+		return Config{}
+	}
+	return readConfig(configPath)
+}
+
+// function to get the asset pattern of a repository, the default one if it has none
+func assetPattern(repo string) string {
+	if pattern, ok := githubAssetPatterns[strings.ToLower(repo)]; ok {
+		return pattern
+	}
+	for _, source := range activeProfile().GitHubSources {
+		if strings.EqualFold(source.Repo, repo) && source.Pattern != "" {
+			return source.Pattern
+		}
+	}
+	return defaultAssetPattern
+}
+
+// function to check if an asset is one that should be installed
+func assetMatches(name string, pattern string) bool {
+	if pattern == defaultAssetPattern {
+		for _, suffix := range excludedAssetSuffixes {
+			if strings.HasSuffix(strings.ToLower(name), suffix) {
+				return false
+			}
+		}
+	}
+	matched, err := path.Match(pattern, name)
+	return err == nil && matched
+}
+
+// addGitHubSource records a repository in the profile so upgrade and list know to look at its releases
+func (config *Config) addGitHubSource(repo string, pattern string) {
+	for i := range config.GitHubSources {
+		if strings.EqualFold(config.GitHubSources[i].Repo, repo) {
+			config.GitHubSources[i].Repo = repo
+			if pattern != "" {
+				config.GitHubSources[i].Pattern = pattern
+			}
+			return
+		}
+	}
+	config.GitHubSources = append(config.GitHubSources, GitHubSource{Repo: repo, Pattern: pattern})
+}
+
+func (config *Config) removeGitHubSource(repo string) {
+	config.GitHubSources = slices.DeleteFunc(config.GitHubSources, func(source GitHubSource) bool {
+		return strings.EqualFold(source.Repo, repo)
+	})
+}
+
+func (githubProvider) Search(query string, gameVersion string) ([]modrinth.SearchHit, error) {
+	return nil, ErrNoSearch
+}
+
+func (githubProvider) Project(slugOrID string) (*modrinth.Project, error) {
+	owner, repo, err := parseGitHubRepo(slugOrID)
+	if err != nil {
+		return nil, err
+	}
+	repository, err := githubClient.GetRepository(context.Background(), owner, repo)
+	if err != nil {
+		return nil, githubError(err)
+	}
+	project := githubProject(*repository)
+	return &project, nil
+}
+
+func (provider githubProvider) Projects(projectIDs []string) ([]modrinth.Project, error) {
+	var projects []modrinth.Project
+	for _, projectID := range projectIDs {
+		project, err := provider.Project(projectID)
+		if isNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		projects = append(projects, *project)
+	}
+	return projects, nil
+}
+
+// Versions has a version for every matching asset of every release, drafts are left out
+func (provider githubProvider) Versions(slugOrID string, gameVersion string) ([]modrinth.Version, error) {
+	project, err := provider.Project(slugOrID)
+	if err != nil {
+		return nil, err
+	}
+	repo := project.Slug
+	owner, name, _ := parseGitHubRepo(repo)
+	releases, err := githubClient.ListReleases(context.Background(), owner, name)
+	if err != nil {
+		return nil, githubError(err)
+	}
+
+	var versions []modrinth.Version
+	pattern := assetPattern(repo)
+	inspected := 0
+	for _, release := range releases {
+		if release.Draft {
+			continue
+		}
+		inspect := inspected < inspectedReleases
+		inspected++
+		for _, asset := range release.Assets {
+			if !assetMatches(asset.Name, pattern) {
+				continue
+			}
+			if version, ok := githubVersion(repo, release, asset, gameVersion, inspect); ok {
+				versions = append(versions, version)
+			}
+		}
+	}
+	return versions, nil
+}
+
+func (githubProvider) Version(versionID string) (*modrinth.Version, error) {
+	repo, tag, assetName, err := parseGitHubVersionID(versionID)
+	if err != nil {
+		return nil, err
+	}
+	owner, name, err := parseGitHubRepo(repo)
+	if err != nil {
+		return nil, err
+	}
+	release, err := githubClient.GetReleaseByTag(context.Background(), owner, name, tag)
+	if err != nil {
+		return nil, githubError(err)
+	}
+	for _, asset := range release.Assets {
+		if asset.Name != assetName {
+			continue
+		}
+		version, _ := githubVersion(repo, *release, asset, activeProfile().GameVersion, true)
+		return &version, nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrModNotFound, versionID)
+}
+
+// LookupFiles finds local files among the release assets of the profile's GitHub sources, by their
+// SHA256 digest or, for assets uploaded before GitHub kept digests, by name and size
+func (githubProvider) LookupFiles(files map[string]string) (map[string]modrinth.Version, error) {
+	versions := map[string]modrinth.Version{}
+	sources := activeProfile().GitHubSources
+	if len(sources) == 0 {
+		return versions, nil
+	}
+
+	type localFile struct {
+		hash   string
+		path   string
+		name   string
+		size   int64
+		digest string
+	}
+	var local []localFile
+	for hash, filePath := range files {
+		info, err := os.Stat(filePath)
+		if err != nil {
+			return nil, err
+		}
+		digest, err := sha256File(filePath)
+		if err != nil {
+			return nil, err
+		}
+		local = append(local, localFile{hash, filePath, path.Base(filePath), info.Size(), "sha256:" + digest})
+	}
+
+	for _, source := range sources {
+		owner, name, err := parseGitHubRepo(source.Repo)
+		if err != nil {
+			continue
+		}
+		releases, err := githubClient.ListReleases(context.Background(), owner, name)
+		// a repository that was deleted or renamed only leaves out its own mods
+		if github.IsNotFound(err) {
+			fmt.Printf("%sGitHub repository %s isn't there anymore, its mods are left as they are%s\n", Yellow, source.Repo, Reset)
+			continue
+		}
+		if err != nil {
+			return nil, githubError(err)
+		}
+		for _, release := range releases {
+			for _, asset := range release.Assets {
+				index := slices.IndexFunc(local, func(file localFile) bool {
+					if asset.Digest != "" {
+						return asset.Digest == file.digest
+					}
+					return asset.Name == file.name && asset.Size == file.size
+				})
+				if index < 0 {
+					continue
+				}
+				file := local[index]
+				version, _ := githubVersion(source.Repo, release, asset, "", false)
+				// the jar is right here, so its metadata can tell what the name doesn't
+				if len(version.Loaders) == 0 || len(version.GameVersions) == 0 {
+					if info, err := readJarInfoFile(file.path); err == nil {
+						if len(version.Loaders) == 0 {
+							version.Loaders = info.Loaders
+						}
+						if len(version.GameVersions) == 0 {
+							version.GameVersions = info.GameVersions
+						}
+					}
+				}
+				version.Files[0].Hashes.SHA512 = file.hash
+				versions[file.hash] = version
+			}
+		}
+	}
+	return versions, nil
+}
+
+func (githubProvider) DownloadURL(version *modrinth.Version, file modrinth.File) (string, error) {
+	return file.URL, nil
+}
+
+func (githubProvider) ProjectURL(project *modrinth.Project) string {
+	return "https://github.com/" + project.Slug
+}
+
+// function to describe a repository as a project, its slug is the full name like "owner/repo"
+func githubProject(repository github.Repository) modrinth.Project {
+	project := modrinth.Project{
+		ID:          "gh:" + repository.FullName,
+		Slug:        repository.FullName,
+		Title:       repository.Name,
+		Description: repository.Description,
+		ProjectType: "mod",
+		Followers:   repository.Stars,
+		IssuesURL:   repository.HTMLURL + "/issues",
+		SourceURL:   repository.HTMLURL,
+		WikiURL:     repository.Homepage,
+		Published:   repository.CreatedAt,
+		Updated:     repository.PushedAt,
+	}
+	if repository.Archived {
+		project.Status = "archived"
+	}
+	if repository.License != nil {
+		project.License = modrinth.License{ID: repository.License.SPDXID, Name: repository.License.Name}
+	}
+	return project
+}
+
+// function to describe a release asset as a version. Its game versions and loaders come from the asset's
+// name, or if that doesn't tell and inspect is set, from the metadata in the jar. If gameVersion is one
+// the asset works with it's in the game versions. Returns false if the loader couldn't be told.
+func githubVersion(repo string, release github.Release, asset github.Asset, gameVersion string, inspect bool) (modrinth.Version, bool) {
+	version := modrinth.Version{
+		ID:            "gh:" + repo + "@" + release.TagName + "/" + asset.Name,
+		ProjectID:     "gh:" + repo,
+		Name:          cmp.Or(release.Name, release.TagName),
+		VersionNumber: release.TagName,
+		Changelog:     release.Body,
+		DatePublished: release.PublishedAt,
+		Downloads:     asset.DownloadCount,
+		Status:        "listed",
+		VersionType:   modrinth.VersionTypeRelease,
+		Files: []modrinth.File{{
+			URL:      asset.BrowserDownloadURL,
+			Filename: asset.Name,
+			Primary:  true,
+			Size:     asset.Size,
+			Hashes:   modrinth.Hashes{SHA256: assetSHA256(asset)},
+		}},
+	}
+	if release.Prerelease {
+		version.VersionType = modrinth.VersionTypeBeta
+	}
+
+	loaders, namedVersions := guessFromFilename(asset.Name)
+	version.Loaders = loaders
+	version.GameVersions = namedVersions
+	if gameVersion != "" && slices.ContainsFunc(namedVersions, func(named string) bool {
+		return filenameVersionAllows(named, gameVersion)
+	}) {
+		version.GameVersions = append(version.GameVersions, gameVersion)
+	}
+
+	// assets uploaded before GitHub kept digests are read too, so there's a hash to check the install against
+	if inspect && (len(loaders) == 0 || len(namedVersions) == 0 || version.Files[0].Hashes.SHA256 == "") {
+		if info, ok := githubJarInfo(asset); ok {
+			version.Files[0].Hashes.SHA1 = info.SHA1
+			version.Files[0].Hashes.SHA512 = info.SHA512
+			if len(loaders) == 0 {
+				version.Loaders = info.Loaders
+			}
+			if len(namedVersions) == 0 {
+				version.GameVersions = info.GameVersions
+				if gameVersion != "" && info.allowsGameVersion(gameVersion) {
+					version.GameVersions = append(version.GameVersions, gameVersion)
+				}
+			}
+		}
+	}
+	return version, len(version.Loaders) > 0
+}
+
+// githubJarInfo gets the metadata of an asset's jar, it's downloaded once and kept in the metadata cache.
+// The jar is put into the jar cache too, so installing it doesn't download it again.
+func githubJarInfo(asset github.Asset) (JarInfo, bool) {
+	var info JarInfo
+	dir := getMetadataDir("jars")
+	name := metadataName("gh:" + strconv.FormatInt(asset.ID, 10))
+	if readMetadata(dir, name, &info) {
+		return info, true
+	}
+	if isOffline() || asset.BrowserDownloadURL == "" {
+		return info, false
+	}
+
+	req, err := http.NewRequest(http.MethodGet, asset.BrowserDownloadURL, nil)
+	if err != nil {
+		return info, false
+	}
+	req.Header.Set("User-Agent", FullVersion)
+	resp, err := githubDownloadClient.Do(req)
+	if err != nil {
+		return info, false
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return info, false
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return info, false
+	}
+
+	if digest := assetSHA256(asset); digest != "" {
+		sum := sha256.Sum256(data)
+		if hex.EncodeToString(sum[:]) != digest {
+			return info, false
+		}
+	}
+	info, err = readJarInfo(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return info, false
+	}
+	sha1Sum := sha1.Sum(data)
+	sha512Sum := sha512.Sum512(data)
+	info.SHA1 = hex.EncodeToString(sha1Sum[:])
+	info.SHA512 = hex.EncodeToString(sha512Sum[:])
+	writeMetadata(dir, name, info)
+	cacheJarData(data, info.SHA512)
+	return info, true
+}
+
+// function to add a jar that's only in memory to the jar cache
+func cacheJarData(data []byte, sha512 string) {
+	if err := os.MkdirAll(getJarCacheDir(), 0755); err != nil {
+		return
+	}
+	temp, err := os.CreateTemp(getJarCacheDir(), ".download-*")
+	if err != nil {
+		return
+	}
+	defer func(name string) {
+		_ = os.Remove(name)
+	}(temp.Name())
+	_, err = temp.Write(data)
+	if closeErr := temp.Close(); err == nil && closeErr == nil {
+		storeJar(temp.Name(), sha512)
+	}
+}
+
+func readJarInfoFile(filePath string) (JarInfo, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return JarInfo{}, err
+	}
+	defer func(file *os.File) {
+		_ = file.Close()
+	}(file)
+	stat, err := file.Stat()
+	if err != nil {
+		return JarInfo{}, err
+	}
+	return readJarInfo(file, stat.Size())
+}
+
+// function to get the SHA256 digest GitHub keeps for an asset, empty if it has none
+func assetSHA256(asset github.Asset) string {
+	digest, ok := strings.CutPrefix(asset.Digest, "sha256:")
+	if !ok {
+		return ""
+	}
+	return digest
+}
+
+func sha256File(filePath string) (string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer func(file *os.File) {
+		_ = file.Close()
+	}(file)
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
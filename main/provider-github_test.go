@@ -0,0 +1,134 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"slices"
+	"testing"
+	"time"
+
+	"gorium/github"
+	"gorium/github/githubtest"
+	"gorium/modrinth"
+)
+
+// the fixtures of the fake GitHub server: example/tiny-tweaks names loaders and game versions in its
+// asset names, example/plain-mod only has them in its jars, example/forge-only is for Forge
+const githubFixtures = "../github/githubtest/testdata/basic"
+
+// useGitHub starts a fake GitHub server, without the repositories in missing
+func useGitHub(t *testing.T, missing ...string) {
+	t.Helper()
+	fixtures, err := githubtest.LoadFixtures(githubFixtures)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fixtures.Repositories = slices.DeleteFunc(fixtures.Repositories, func(repository github.Repository) bool {
+		return slices.Contains(missing, repository.FullName)
+	})
+	for _, repo := range missing {
+		delete(fixtures.Releases, repo)
+	}
+	server := githubtest.NewServer(fixtures)
+	t.Cleanup(server.Close)
+	t.Setenv(githubAPIEnv, server.URL)
+
+	githubCache = &modrinth.Cache{Dir: t.TempDir(), TTL: responseCache.TTL}
+	githubClient = newGitHubClient()
+}
+
+func TestAddFromGitHub(t *testing.T) {
+	modsPath := newTestProfile(t, ChannelRelease)
+	useGitHub(t)
+
+	runGorium(t, "add", "gh:example/tiny-tweaks")
+
+	// the beta is left out by the channel, the draft and the sources jar are never used
+	expectJars(t, modsPath, "tiny-tweaks-2.0.0+mc1.21.1-fabric.jar")
+	expectLocked(t, modsPath, "gh:example/tiny-tweaks", "gh:example/tiny-tweaks@v2.0.0/tiny-tweaks-2.0.0+mc1.21.1-fabric.jar", ReasonRequested)
+	if sources := activeProfile().GitHubSources; len(sources) != 1 || sources[0].Repo != "example/tiny-tweaks" {
+		t.Fatalf("GitHub sources of the profile are %v", sources)
+	}
+}
+
+func TestAddFromGitHubReadsJars(t *testing.T) {
+	modsPath := newTestProfile(t, "")
+	useGitHub(t)
+
+	// the names of these jars don't tell the game version, their fabric.mod.json does
+	runGorium(t, "add", "gh:example/plain-mod")
+
+	expectJars(t, modsPath, "plain-mod-3.1.jar")
+}
+
+func TestUpgradeFromGitHub(t *testing.T) {
+	modsPath := newTestProfile(t, ChannelRelease)
+	useGitHub(t)
+	runGorium(t, "add", "gh:example/tiny-tweaks@v1.9.0")
+	expectJars(t, modsPath, "tiny-tweaks-1.9.0+mc1.21.1-fabric.jar")
+
+	runGorium(t, "upgrade")
+
+	expectJars(t, modsPath, "tiny-tweaks-2.0.0+mc1.21.1-fabric.jar")
+}
+
+func TestGitHubMissingRepositoryDoesntBlockOtherMods(t *testing.T) {
+	modsPath := newTestProfile(t, "")
+	useGitHub(t)
+	runGorium(t, "add", "gh:example/tiny-tweaks@v2.0.0")
+	runGorium(t, "add", "gh:example/plain-mod")
+	runGorium(t, "add", "sodium")
+
+	useGitHub(t, "example/plain-mod")
+	installed, err := getInstalledMods(modsPath)
+	if err != nil {
+		t.Fatalf("a missing repository failed the lookup: %v", err)
+	}
+	for _, projectID := range []string{"gh:example/tiny-tweaks", "AANobbMI"} {
+		if _, ok := installed[projectID]; !ok {
+			t.Fatalf("%s wasn't found, got %v", projectID, installed)
+		}
+	}
+
+	runGorium(t, "upgrade")
+	expectJars(t, modsPath, "tiny-tweaks-2.1.0-beta.1+mc1.21.1-fabric.jar", "plain-mod-3.1.jar", "sodium-fabric-0.6.0-beta.2+mc1.21.1.jar")
+}
+
+func TestGitHubDigestIsChecked(t *testing.T) {
+	newTestProfile(t, "")
+	jar := []byte("tiny tweaks")
+	sum := sha256.Sum256([]byte("another jar"))
+	asset := github.Asset{
+		Name:               "tiny-tweaks-2.0.0+mc1.21.1-fabric.jar",
+		BrowserDownloadURL: "https://github.com/example/tiny-tweaks/releases/download/v2.0.0/tiny-tweaks-2.0.0+mc1.21.1-fabric.jar",
+		Digest:             "sha256:" + hex.EncodeToString(sum[:]),
+	}
+	release := github.Release{TagName: "v2.0.0", PublishedAt: time.Now()}
+
+	version, ok := githubVersion("example/tiny-tweaks", release, asset, "1.21.1", false)
+	if !ok || version.Files[0].Hashes.SHA256 != hex.EncodeToString(sum[:]) {
+		t.Fatalf("the digest isn't on the file, got %+v", version.Files[0].Hashes)
+	}
+
+	// a jar that doesn't match the digest never makes it into the mods folder
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(jar)
+	}))
+	t.Cleanup(server.Close)
+	modsPath := t.TempDir()
+	progress := newDownloadProgress(1)
+	err := downloadFile(server.URL, modsPath, asset.Name, version.Files[0].Hashes, progress.addFile(asset.Name))
+	if err == nil || dirExists(path.Join(modsPath, asset.Name)) {
+		t.Fatalf("a jar that doesn't match its digest was installed, error %v", err)
+	}
+
+	// without a digest and without the jar being read there's nothing to check the download against
+	asset.Digest = ""
+	version, _ = githubVersion("example/tiny-tweaks", release, asset, "1.21.1", false)
+	if err := resolveDownloadURL(&version); err == nil {
+		t.Fatal("a jar without any hash would be installed")
+	}
+}
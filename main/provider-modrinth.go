@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+
+	"gorium/modrinth"
+)
+
+// modrinthProvider gets mods from Modrinth, its IDs are used as they are
+type modrinthProvider struct{}
+
+func (modrinthProvider) Name() string {
+	return "modrinth"
+}
+
+func (modrinthProvider) Prefix() string {
+	return "mr"
+}
+
+func (modrinthProvider) Search(query string, gameVersion string) ([]modrinth.SearchHit, error) {
+	params := modrinth.SearchParams{Query: query, Limit: 100}
+	if gameVersion != "" {
+		params.Facets = [][]string{{"versions:" + gameVersion}}
+	}
+	results, err := modrinthClient.Search(context.Background(), params)
+	if err != nil {
+		return nil, err
+	}
+	return results.Hits, nil
+}
+
+func (modrinthProvider) Project(slugOrID string) (*modrinth.Project, error) {
+	return modrinthClient.GetProject(context.Background(), slugOrID)
+}
+
+func (modrinthProvider) Projects(projectIDs []string) ([]modrinth.Project, error) {
+	return modrinthClient.GetProjects(context.Background(), projectIDs)
+}
+
+func (modrinthProvider) Versions(slugOrID string, gameVersion string) ([]modrinth.Version, error) {
+	var filter *modrinth.VersionFilter
+	if gameVersion != "" {
+		filter = &modrinth.VersionFilter{GameVersions: []string{gameVersion}}
+	}
+	return modrinthClient.GetProjectVersions(context.Background(), slugOrID, filter)
+}
+
+func (modrinthProvider) Version(versionID string) (*modrinth.Version, error) {
+	return modrinthClient.GetVersion(context.Background(), versionID)
+}
+
+func (modrinthProvider) LookupFiles(files map[string]string) (map[string]modrinth.Version, error) {
+	var hashes []string
+	for hash := range files {
+		hashes = append(hashes, hash)
+	}
+	return modrinthClient.VersionsFromHashes(context.Background(), hashes, modrinth.SHA512)
+}
+
+func (modrinthProvider) DownloadURL(version *modrinth.Version, file modrinth.File) (string, error) {
+	return file.URL, nil
+}
+
+func (modrinthProvider) ProjectURL(project *modrinth.Project) string {
+	projectType := project.ProjectType
+	if projectType == "" {
+		projectType = "mod"
+	}
+	return "https://modrinth.com/" + projectType + "/" + project.Slug
+}
+
+func (modrinthProvider) LatestVersions(hashes []string, loaders []string, gameVersion string, versionTypes []string) (map[string]modrinth.Version, error) {
+	filter := modrinth.UpdateFilter{
+		Loaders:      loaders,
+		GameVersions: []string{gameVersion},
+		VersionTypes: versionTypes,
+	}
+	return modrinthClient.LatestVersionsFromHashes(context.Background(), hashes, modrinth.SHA512, filter)
+}
@@ -0,0 +1,271 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"gorium/modrinth"
+)
+
+// Provider is a source of mods. Providers describe their projects and versions with Modrinth's models,
+// since those already cover everything gorium needs. Project and version IDs of every provider but
+// Modrinth start with the provider's prefix, like "cf:238222", so IDs from different sources never mix.
+type Provider interface {
+	// Name is recorded in the lockfile for mods installed from this provider
+	Name() string
+	// Prefix is put before project names to pick this provider, like "cf" in gorium add cf:jei
+	Prefix() string
+	// Search finds mods, gameVersion narrows the results down if it's set
+	Search(query string, gameVersion string) ([]modrinth.SearchHit, error)
+	Project(slugOrID string) (*modrinth.Project, error)
+	Projects(projectIDs []string) ([]modrinth.Project, error)
+	// Versions lists the versions of a project in any order, only those for gameVersion if it's set
+	Versions(slugOrID string, gameVersion string) ([]modrinth.Version, error)
+	Version(versionID string) (*modrinth.Version, error)
+	// LookupFiles finds the versions of local files, files is SHA512 hash to path and so is the result.
+	// Files the provider doesn't know are left out.
+	LookupFiles(files map[string]string) (map[string]modrinth.Version, error)
+	DownloadURL(version *modrinth.Version, file modrinth.File) (string, error)
+	// ProjectURL is the web page of a project
+	ProjectURL(project *modrinth.Project) string
+}
+
+// UpdateChecker is implemented by providers that can find updates for many files in one go,
+// providers without it are asked for the versions of every project instead
+type UpdateChecker interface {
+	LatestVersions(hashes []string, loaders []string, gameVersion string, versionTypes []string) (map[string]modrinth.Version, error)
+}
+
+// providers are asked in this order when looking up local files, the first one is the default
+var providers = []Provider{
+	modrinthProvider{},
+	curseforgeProvider{},
+	githubProvider{},
+}
+
+// ErrNoProvider is returned for a provider prefix gorium doesn't know
+var ErrNoProvider = errors.New("unknown mod source")
+
+// ErrModNotFound is returned by providers for projects and versions they don't have
+var ErrModNotFound = errors.New("mod not found")
+
+// ProviderError is an error from a provider other than Modrinth, so it's reported with the right name
+type ProviderError struct {
+	Provider string
+	Err      error
+}
+
+func (e *ProviderError) Error() string {
+	return e.Provider + ": " + e.Err.Error()
+}
+
+func (e *ProviderError) Unwrap() error {
+	return e.Err
+}
+
+// function to check if a provider doesn't have what was asked for
+func isNotFound(err error) bool {
+	return modrinth.IsNotFound(err) || errors.Is(err, ErrModNotFound)
+}
+
+// function to split a name like "cf:jei" into its provider and the rest, names without a prefix are Modrinth's
+func splitProvider(name string) (Provider, string, error) {
+	prefix, rest, found := strings.Cut(name, ":")
+	if !found {
+		return providers[0], name, nil
+	}
+	for _, provider := range providers {
+		if provider.Prefix() == prefix {
+			if isDefaultProvider(provider) {
+				return provider, rest, nil
+			}
+			return provider, name, nil
+		}
+	}
+	return nil, "", fmt.Errorf("%w %q, use one of %s", ErrNoProvider, prefix, strings.Join(providerPrefixes(), ", "))
+}
+
+func providerPrefixes() []string {
+	var prefixes []string
+	for _, provider := range providers {
+		prefixes = append(prefixes, provider.Prefix()+":")
+	}
+	return prefixes
+}
+
+// function to get the provider a project or version ID belongs to
+func providerFor(id string) Provider {
+	provider, _, err := splitProvider(id)
+	if err != nil {
+		return providers[0]
+	}
+	return provider
+}
+
+func isDefaultProvider(provider Provider) bool {
+	return provider.Name() == providers[0].Name()
+}
+
+// function to get the loaders whose mods work with the given one
+func compatibleLoaders(loader string) []string {
+	switch loader {
+	case "quilt":
+		return []string{loader, "fabric"}
+	case "neoforge":
+		return []string{loader, "forge"}
+	default:
+		return []string{loader}
+	}
+}
+
+// function to fill in the download address of the primary file of a version, not every provider has it up front.
+// A file without any hash is refused, since its download couldn't be checked.
+func resolveDownloadURL(version *modrinth.Version) error {
+	primary := primaryFile(version)
+	for i := range version.Files {
+		if version.Files[i].Filename != primary.Filename {
+			continue
+		}
+		if version.Files[i].Hashes == (modrinth.Hashes{}) {
+			return fmt.Errorf("%s comes without a hash, gorium can't check its download and won't install it", primary.Filename)
+		}
+		url, err := providerFor(version.ProjectID).DownloadURL(version, version.Files[i])
+		if err != nil {
+			return err
+		}
+		version.Files[i].URL = url
+	}
+	return nil
+}
+
+// function to fill in the SHA512 hash of the primary file of a version once it's downloaded to dir,
+// not every provider knows it up front
+func fillDownloadedHash(version *modrinth.Version, dir string) {
+	primary := primaryFile(version)
+	for i := range version.Files {
+		if version.Files[i].Filename == primary.Filename && version.Files[i].Hashes.SHA512 == "" {
+			version.Files[i].Hashes.SHA512 = hashFileSHA512(path.Join(dir, primary.Filename))
+		}
+	}
+}
+
+// function to tell that a provider other than Modrinth can't be used, the command goes on without it
+func warnProviderSkipped(provider Provider, err error) {
+	name := provider.Name()
+	var providerErr *ProviderError
+	if errors.As(err, &providerErr) {
+		name = providerErr.Provider
+	}
+	fmt.Printf("%sSkipping %s, its mods are left as they are: %s%s\n", Yellow, name, errorMessage(err), Reset)
+}
+
+// fetchUpdates finds the newest version allowed by the channel for every installed file, keyed by hash.
+// Updates of held mods are returned separately, they are only shown.
+func fetchUpdates(current map[string]modrinth.Version, configData Config, backward []bool) (map[string]modrinth.Version, map[string]modrinth.Version, error) {
+	updates := map[string]modrinth.Version{}
+	heldUpdates := map[string]modrinth.Version{}
+
+	// hashes are grouped by provider and release channel, since batch lookups filter per request
+	type group struct {
+		provider Provider
+		channel  string
+		held     bool
+	}
+	groups := map[group][]string{}
+	for hash, version := range current {
+		key := group{providerFor(version.ProjectID), configData.channelFor(version.ProjectID), configData.isHeld(version.ProjectID)}
+		groups[key] = append(groups[key], hash)
+	}
+
+	unreachable := map[string]bool{}
+	for key, hashes := range groups {
+		if unreachable[key.provider.Name()] {
+			continue
+		}
+		found := updates
+		if key.held {
+			found = heldUpdates
+		}
+
+		if checker, ok := key.provider.(UpdateChecker); ok {
+			latest, err := checker.LatestVersions(hashes, compatibleLoaders(configData.Loader), configData.GameVersion, allowedVersionTypes(key.channel))
+			if err != nil {
+				return nil, nil, err
+			}
+			for hash, version := range latest {
+				found[hash] = version
+			}
+			continue
+		}
+
+		for _, hash := range hashes {
+			versions, err := fetchCompatibleVersions(current[hash].ProjectID, configData, backward)
+			// a source other than Modrinth failing shouldn't hold back the rest of the upgrade
+			if isNotFound(err) && !isDefaultProvider(key.provider) {
+				fmt.Printf("%s%s isn't there anymore, it's left as it is%s\n", Yellow, current[hash].ProjectID, Reset)
+				continue
+			}
+			if err != nil && !isDefaultProvider(key.provider) {
+				warnProviderSkipped(key.provider, err)
+				unreachable[key.provider.Name()] = true
+				break
+			}
+			if err != nil {
+				return nil, nil, err
+			}
+			if len(versions) > 0 {
+				found[hash] = versions[0]
+			}
+		}
+	}
+	return updates, heldUpdates, nil
+}
+
+// function to look up the versions of local files with every provider, keyed by hash. files is hash to filename.
+// Offline, or if a provider can't be reached, the metadata cache answers instead. Only Modrinth being
+// unreachable turns on offline mode, the other providers are optional.
+func fetchVersionsFromFiles(dir string, files map[string]string) (map[string]modrinth.Version, error) {
+	versions := map[string]modrinth.Version{}
+	remaining := map[string]string{}
+	for hash, filename := range files {
+		remaining[hash] = path.Join(dir, filename)
+	}
+
+	for _, provider := range providers {
+		if len(remaining) == 0 {
+			break
+		}
+		var found map[string]modrinth.Version
+		var err error
+		if !isOffline() {
+			found, err = provider.LookupFiles(remaining)
+		}
+		unreachable := isNetworkError(err) || errors.Is(err, modrinth.ErrOffline)
+		if unreachable && isDefaultProvider(provider) {
+			goOffline(true)
+		}
+		// the other providers are optional, one refusing to answer, like for a wrong key, is treated as unreachable
+		if err != nil && !unreachable && !isDefaultProvider(provider) {
+			warnProviderSkipped(provider, err)
+			unreachable = true
+		}
+		if isOffline() || unreachable {
+			var unknown []string
+			for hash := range remaining {
+				unknown = append(unknown, hash)
+			}
+			found = cachedVersionsFromHashes(unknown)
+		} else if err != nil {
+			return nil, err
+		} else {
+			rememberVersions(found)
+		}
+		for hash, version := range found {
+			versions[hash] = version
+			delete(remaining, hash)
+		}
+	}
+	return versions, nil
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package main
+
+import "errors"
+
+// reflinkFile isn't supported here, hardlinks and copies are used instead
+func reflinkFile(source string, destination string) error {
+	return errors.ErrUnsupported
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"gorium/cli"
+	"gorium/modrinth"
+)
+
+// InstalledMod is a mod file in the mods folder that one of the providers recognises
+type InstalledMod struct {
+	Hash     string
+	Filename string
+	Version  modrinth.Version
+	Project  modrinth.Project
+}
+
+// function to get mods in a folder that a provider knows about, keyed by project ID
+func getInstalledMods(modsPath string) (map[string]InstalledMod, error) {
+	installed := map[string]InstalledMod{}
+	if !dirExists(modsPath) {
+		return installed, nil
+	}
+
+	localFiles := getSHA512FilesFromDirectory(modsPath)
+	if len(localFiles) < 1 {
+		return installed, nil
+	}
+
+	versions, err := fetchVersionsFromFiles(modsPath, localFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	var projectIDs []string
+	for _, version := range versions {
+		projectIDs = append(projectIDs, version.ProjectID)
+	}
+	projects, err := fetchProjects(projectIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for hash, version := range versions {
+		installed[version.ProjectID] = InstalledMod{
+			Hash:     hash,
+			Filename: localFiles[hash],
+			Version:  version,
+			Project:  projects[version.ProjectID],
+		}
+	}
+	return installed, nil
+}
+
+// function to find an installed mod by its slug or project ID
+func findInstalledMod(installed map[string]InstalledMod, slugOrID string) (InstalledMod, bool) {
+	if mod, ok := installed[slugOrID]; ok {
+		return mod, true
+	}
+	for _, mod := range installed {
+		if mod.Project.Slug == slugOrID {
+			return mod, true
+		}
+	}
+	return InstalledMod{}, false
+}
+
+// function to let the user pick one of the installed mods, returns the project ID
+func chooseInstalledMod(installed map[string]InstalledMod, prompt string) string {
+	var mods []InstalledMod
+	for _, mod := range installed {
+		mods = append(mods, mod)
+	}
+	sort.Slice(mods, func(i, j int) bool {
+		return mods[i].Project.Title < mods[j].Project.Title
+	})
+
+	menu := cli.NewMenu(prompt)
+	for _, mod := range mods {
+		menu.AddItem(fmt.Sprintf("%s %s[%s%s%s]", mod.Project.Title, Reset, Yellow, mod.Filename, Reset), mod.Version.ProjectID)
+	}
+	return menu.Display()
+}
+
+// function to check if any of the installed mods requires the given one
+func isRequired(installed map[string]InstalledMod, mod InstalledMod) bool {
+	for _, other := range installed {
+		if other.Version.ProjectID == mod.Version.ProjectID {
+			continue
+		}
+		for _, dependency := range other.Version.Dependencies {
+			if dependency.DependencyType == "required" && (dependency.ProjectID == mod.Version.ProjectID || dependency.VersionID == mod.Version.ID) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// removeMod deletes an installed mod and its lockfile entry. With removeDependencies set,
+// mods that were only installed as dependencies and aren't needed anymore are removed too.
+func removeMod(slugOrID string, removeDependencies bool) {
+	configPath, _ := getConfigPath()
+	configData := readConfig(configPath)
+	if len(configData.Name) == 0 {
+		fmt.Println(Red + "No profile found, type gorium profile create" + Reset)
+		return
+	}
+	modsPath := configData.ModsFolder
+
+	installed, err := getInstalledMods(modsPath)
+	if err != nil {
+		printError(err)
+		return
+	}
+	if slugOrID != "" {
+		lock := readLockFile(modsPath)
+		if mod := lock.findUnmanaged(slugOrID); mod != nil {
+			if err := os.Remove(path.Join(modsPath, mod.Filename)); err != nil && !os.IsNotExist(err) {
+				checkError(err)
+			}
+			fmt.Printf("[%sRemoved%s] %s [unmanaged]\n", Yellow, Reset, mod.Filename)
+			lock.removeUnmanaged(mod.Filename)
+			writeLockFile(modsPath, lock)
+			return
+		}
+	}
+	if len(installed) == 0 {
+		fmt.Println("There's no mods, type gorium add")
+		return
+	}
+
+	if slugOrID == "" {
+		slugOrID = chooseInstalledMod(installed, "Select the mod you want to remove")
+		if slugOrID == "" {
+			return
+		}
+	}
+
+	mod, ok := findInstalledMod(installed, slugOrID)
+	if !ok {
+		fmt.Printf("%s%s is not installed%s\n", Red, slugOrID, Reset)
+		return
+	}
+
+	lock := readLockFile(modsPath)
+
+	toRemove := []InstalledMod{mod}
+	delete(installed, mod.Version.ProjectID)
+
+	if isRequired(installed, mod) {
+		fmt.Printf("%sWarning: other installed mods require %s%s\n", Yellow, mod.Project.Title, Reset)
+	}
+
+	// removing one dependency may leave its own dependencies unused, so repeat until nothing changes
+	for removeDependencies {
+		removeDependencies = false
+		for projectID, candidate := range installed {
+			locked := lock.findMod(projectID)
+			if locked == nil || locked.Reason != ReasonDependency || isRequired(installed, candidate) {
+				continue
+			}
+			toRemove = append(toRemove, candidate)
+			delete(installed, projectID)
+			removeDependencies = true
+		}
+	}
+
+	for _, mod := range toRemove {
+		err := os.Remove(path.Join(modsPath, mod.Filename))
+		checkError(err)
+		lock.removeMod(mod.Version.ProjectID)
+		if repo, ok := strings.CutPrefix(mod.Version.ProjectID, "gh:"); ok {
+			configData.removeGitHubSource(repo)
+			saveProfile(configData)
+		}
+		fmt.Printf("[%sRemoved%s] %s (%s)\n", Yellow, Reset, mod.Project.Title, mod.Filename)
+	}
+
+	if dirExists(getLockFilePath(modsPath)) {
+		writeLockFile(modsPath, lock)
+	}
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path"
+
+	"gorium/modrinth"
+)
+
+// syncMods makes the mods folder of the active profile match its lockfile.
+// Jars that a provider recognises but the lockfile doesn't list are removed, unknown jars are left alone.
+// Missing unmanaged jars are put back from the jar cache or the address they were downloaded from.
+func syncMods() {
+	configPath, _ := getConfigPath()
+	configData := readConfig(configPath)
+	if len(configData.Name) == 0 {
+		fmt.Println(Red + "No profile found, type gorium profile create" + Reset)
+		return
+	}
+
+	modsPath := configData.ModsFolder
+	if !dirExists(getLockFilePath(modsPath)) {
+		fmt.Printf("%sNo %s found in %s%s\n", Red, LockFileName, modsPath, Reset)
+		return
+	}
+	added, removed, verified, failed, err := syncFolder(modsPath, readLockFile(modsPath))
+	if err != nil {
+		printError(err)
+		return
+	}
+	printSyncSummary(added, removed, verified, failed)
+}
+
+// syncFolder puts the jars of the lockfile into modsPath and removes the ones it doesn't list, it returns
+// the names of the jars that were added, removed, verified and that failed
+func syncFolder(modsPath string, lock LockFile) ([]string, []string, []string, []string, error) {
+	localFiles := getSHA512FilesFromDirectory(modsPath)
+
+	var added, removed, verified, failed []string
+	var filesToDownload []map[string]string
+	locked := map[string]bool{}
+	// names of the jars that get replaced, the old jars stay until their replacements are in place
+	replaced := map[string]bool{}
+
+	for _, mod := range lock.Mods {
+		locked[mod.SHA512] = true
+
+		if filename, ok := localFiles[mod.SHA512]; ok {
+			if filename != mod.Filename {
+				err := os.Rename(path.Join(modsPath, filename), path.Join(modsPath, mod.Filename))
+				checkError(err)
+			}
+			verified = append(verified, mod.Filename)
+			continue
+		}
+
+		replaced[mod.Filename] = true
+		filesToDownload = append(filesToDownload, map[string]string{
+			"url":      mod.URL,
+			"filename": mod.Filename,
+			"sha512":   mod.SHA512,
+		})
+	}
+
+	var missingUnmanaged []UnmanagedMod
+	for _, mod := range lock.Unmanaged {
+		locked[mod.SHA512] = true
+
+		if filename, ok := localFiles[mod.SHA512]; ok {
+			if filename != mod.Filename {
+				err := os.Rename(path.Join(modsPath, filename), path.Join(modsPath, mod.Filename))
+				checkError(err)
+			}
+			verified = append(verified, mod.Filename)
+			continue
+		}
+		replaced[mod.Filename] = true
+		missingUnmanaged = append(missingUnmanaged, mod)
+	}
+
+	unlisted := map[string]string{}
+	for hash, filename := range localFiles {
+		if !locked[hash] && !replaced[filename] && dirExists(path.Join(modsPath, filename)) {
+			unlisted[hash] = filename
+		}
+	}
+
+	managed := map[string]modrinth.Version{}
+	if len(unlisted) > 0 {
+		var err error
+		managed, err = fetchVersionsFromFiles(modsPath, unlisted)
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+	}
+
+	// the errors are shown as the downloads finish, the failed mods are listed in the summary below
+	downloadErr := downloadFilesConcurrently(modsPath, filesToDownload)
+
+	// downloads only replace a jar once they are complete and verified, so a failed one leaves the old jar in place
+	failedProjects := map[string]bool{}
+	for _, mod := range lock.Mods {
+		if contains(verified, mod.Filename) {
+			continue
+		}
+		filePath := path.Join(modsPath, mod.Filename)
+		if downloadErr != nil && (!dirExists(filePath) || hashFileSHA512(filePath) != mod.SHA512) {
+			failed = append(failed, mod.Filename)
+			failedProjects[mod.ProjectID] = true
+			continue
+		}
+		added = append(added, mod.Filename)
+	}
+
+	// unlisted jars go once the downloads are done, an older jar of a mod whose download failed stays
+	for hash, version := range managed {
+		if failedProjects[version.ProjectID] {
+			continue
+		}
+		err := os.Remove(path.Join(modsPath, localFiles[hash]))
+		checkError(err)
+		removed = append(removed, localFiles[hash])
+	}
+
+	for _, mod := range missingUnmanaged {
+		filePath := path.Join(modsPath, mod.Filename)
+		if err := restoreUnmanaged(modsPath, mod); err != nil || hashFileSHA512(filePath) != mod.SHA512 {
+			failed = append(failed, mod.Filename)
+			continue
+		}
+		added = append(added, mod.Filename)
+	}
+
+	return added, removed, verified, failed, nil
+}
+
+func printSyncSummary(added []string, removed []string, verified []string, failed []string) {
+	for _, filename := range added {
+		fmt.Printf("[%sAdded%s] %s\n", Green, Reset, filename)
+	}
+	for _, filename := range removed {
+		fmt.Printf("[%sRemoved%s] %s\n", Yellow, Reset, filename)
+	}
+	for _, filename := range failed {
+		fmt.Printf("[%sFailed%s] %s\n", Red, Reset, filename)
+	}
+
+	fmt.Printf("%d added, %d removed, %d verified", len(added), len(removed), len(verified))
+	if len(failed) > 0 {
+		fmt.Printf(", %s%d failed%s\n", Red, len(failed), Reset)
+		os.Exit(1)
+	}
+	fmt.Println()
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path"
+	"runtime"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// environment variable holding a Modrinth personal access token, it wins over the token file
+const modrinthTokenEnv = "GORIUM_MODRINTH_TOKEN"
+
+// function to get the file the Modrinth token is kept in, only its owner may read it
+func getTokenPath() string {
+	_, configFolder := getConfigPath()
+	return path.Join(configFolder, "modrinth-token")
+}
+
+// function to get the Modrinth personal access token, empty if there is none.
+// A token file other users can read is ignored, the token may have leaked already.
+func getModrinthToken() string {
+	if token := strings.TrimSpace(os.Getenv(modrinthTokenEnv)); token != "" {
+		return token
+	}
+
+	tokenPath := getTokenPath()
+	info, err := os.Stat(tokenPath)
+	if err != nil {
+		return ""
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0077 != 0 {
+		fmt.Printf("%sIgnoring %s because other users can read it, run chmod 600 on it%s\n", Yellow, tokenPath, Reset)
+		return ""
+	}
+	token, err := os.ReadFile(tokenPath)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(token))
+}
+
+// tokenCommand shows, sets or removes the Modrinth token. The token itself is never printed.
+func tokenCommand(args []string) {
+	if len(args) == 0 {
+		switch {
+		case os.Getenv(modrinthTokenEnv) != "":
+			fmt.Printf("Modrinth token is set through %s\n", modrinthTokenEnv)
+		case modrinthClient.Token != "":
+			fmt.Printf("Modrinth token is set in %s\n", getTokenPath())
+		default:
+			fmt.Println("No Modrinth token, type gorium token set")
+		}
+		return
+	}
+
+	switch args[0] {
+	case "set":
+		token := readToken()
+		if token == "" {
+			fmt.Println(Red + "No token entered" + Reset)
+			return
+		}
+		_, configFolder := getConfigPath()
+		err := os.MkdirAll(configFolder, 0755)
+		checkError(err)
+
+		tokenPath := getTokenPath()
+		err = os.WriteFile(tokenPath, []byte(token+"\n"), 0600)
+		checkError(err)
+		// WriteFile keeps the permissions of a file that already existed
+		err = os.Chmod(tokenPath, 0600)
+		checkError(err)
+		fmt.Printf("[%sSaved%s] Modrinth token in %s\n", Green, Reset, tokenPath)
+	case "remove":
+		err := os.Remove(getTokenPath())
+		if os.IsNotExist(err) {
+			fmt.Println("No Modrinth token saved")
+			return
+		}
+		checkError(err)
+		fmt.Printf("[%sRemoved%s] Modrinth token\n", Yellow, Reset)
+	default:
+		fmt.Println(Red + "Unknown command, use gorium token [set/remove]" + Reset)
+	}
+}
+
+// function to read a token from the terminal without echoing it, or from piped input
+func readToken() string {
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		fmt.Print("Enter your Modrinth personal access token: ")
+		token, err := term.ReadPassword(int(os.Stdin.Fd()))
+		fmt.Println()
+		checkError(err)
+		return strings.TrimSpace(string(token))
+	}
+	token, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	return strings.TrimSpace(token)
+}
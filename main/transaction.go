@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path"
+)
+
+// ModsTransaction stages new mod files next to the mods folder and swaps them in all at once.
+// If anything fails the original files are put back, so the instance stays launchable.
+type ModsTransaction struct {
+	modsPath   string
+	stagingDir string
+	backupDir  string
+	backedUp   []string // old files moved into backupDir
+	swappedIn  []string // new files moved into modsPath
+	committed  bool
+}
+
+// function to start a transaction, staging and backup directories live inside the mods folder
+// so that moving files between them is a rename on the same filesystem
+func newModsTransaction(modsPath string) (*ModsTransaction, error) {
+	stagingDir, err := os.MkdirTemp(modsPath, ".gorium-staging-")
+	if err != nil {
+		return nil, fmt.Errorf("error creating staging directory: %w", err)
+	}
+	backupDir, err := os.MkdirTemp(modsPath, ".gorium-backup-")
+	if err != nil {
+		_ = os.RemoveAll(stagingDir)
+		return nil, fmt.Errorf("error creating backup directory: %w", err)
+	}
+	return &ModsTransaction{
+		modsPath:   modsPath,
+		stagingDir: stagingDir,
+		backupDir:  backupDir,
+	}, nil
+}
+
+// verify checks that every staged file is there and has the expected SHA512 hash, keyed by filename
+func (transaction *ModsTransaction) verify(expected map[string]string) error {
+	for filename, hash := range expected {
+		filePath := path.Join(transaction.stagingDir, filename)
+		if !dirExists(filePath) {
+			return fmt.Errorf("%s was not downloaded", filename)
+		}
+		if hash != "" && hashFileSHA512(filePath) != hash {
+			return fmt.Errorf("%s doesn't match its hash", filename)
+		}
+	}
+	return nil
+}
+
+// commit moves oldFiles out of the mods folder and newFiles in from staging.
+// On failure everything done so far is undone before the error is returned.
+func (transaction *ModsTransaction) commit(oldFiles []string, newFiles []string) error {
+	for _, filename := range oldFiles {
+		if !dirExists(path.Join(transaction.modsPath, filename)) {
+			continue
+		}
+		err := os.Rename(path.Join(transaction.modsPath, filename), path.Join(transaction.backupDir, filename))
+		if err != nil {
+			transaction.rollback()
+			return fmt.Errorf("error moving %s to backup: %w", filename, err)
+		}
+		transaction.backedUp = append(transaction.backedUp, filename)
+	}
+
+	for _, filename := range newFiles {
+		err := os.Rename(path.Join(transaction.stagingDir, filename), path.Join(transaction.modsPath, filename))
+		if err != nil {
+			transaction.rollback()
+			return fmt.Errorf("error moving %s into the mods folder: %w", filename, err)
+		}
+		transaction.swappedIn = append(transaction.swappedIn, filename)
+	}
+	transaction.committed = true
+	return nil
+}
+
+// rollback removes new files that were already swapped in and restores the old ones
+func (transaction *ModsTransaction) rollback() {
+	for _, filename := range transaction.swappedIn {
+		if err := os.Remove(path.Join(transaction.modsPath, filename)); err != nil {
+			fmt.Printf("%sError removing %s: %s%s\n", Red, filename, err.Error(), Reset)
+		}
+	}
+	transaction.swappedIn = nil
+
+	// whatever can't be restored stays in backedUp, so cleanup knows to keep the backup
+	var notRestored []string
+	for _, filename := range transaction.backedUp {
+		if err := os.Rename(path.Join(transaction.backupDir, filename), path.Join(transaction.modsPath, filename)); err != nil {
+			fmt.Printf("%sError restoring %s, a copy is kept in %s: %s%s\n", Red, filename, transaction.backupDir, err.Error(), Reset)
+			notRestored = append(notRestored, filename)
+		}
+	}
+	transaction.backedUp = notRestored
+}
+
+// cleanup removes the staging and backup directories, the backup is kept if some file couldn't be restored
+func (transaction *ModsTransaction) cleanup() {
+	_ = os.RemoveAll(transaction.stagingDir)
+	if transaction.committed || len(transaction.backedUp) == 0 {
+		_ = os.RemoveAll(transaction.backupDir)
+	}
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Unmanaged mods are jars added from a file or an address instead of a provider. gorium keeps them in
+// the lockfile with their hash and where they came from, so list and sync know about them, but it can't
+// find updates or dependencies for them.
+
+// UnmanagedMod is a jar in the lockfile that no provider knows
+type UnmanagedMod struct {
+	Filename string `json:"filename"`
+	SHA512   string `json:"sha512"`
+	// Origin is the address the jar was downloaded from or the name of the file it was copied from.
+	// Only the name is kept, since the lockfile is shared and paths on one computer mean nothing on another.
+	Origin string `json:"origin"`
+}
+
+// function to check if an argument of gorium add is an address of a jar
+func isJarURL(arg string) bool {
+	return strings.HasPrefix(arg, "http://") || strings.HasPrefix(arg, "https://")
+}
+
+// function to check if an argument of gorium add is a jar on disk rather than a mod name
+func isLocalJar(arg string) bool {
+	if !strings.HasSuffix(strings.ToLower(arg), ".jar") {
+		return false
+	}
+	return strings.ContainsAny(arg, `/\`) || dirExists(arg)
+}
+
+func (lock *LockFile) findUnmanaged(filenameOrOrigin string) *UnmanagedMod {
+	for i := range lock.Unmanaged {
+		if lock.Unmanaged[i].Filename == filenameOrOrigin || lock.Unmanaged[i].Origin == filenameOrOrigin {
+			return &lock.Unmanaged[i]
+		}
+	}
+	return nil
+}
+
+// setUnmanaged adds an unmanaged mod to the lockfile or replaces the entry of the same file
+func (lock *LockFile) setUnmanaged(mod UnmanagedMod) {
+	if existing := lock.findUnmanaged(mod.Filename); existing != nil {
+		*existing = mod
+		return
+	}
+	lock.Unmanaged = append(lock.Unmanaged, mod)
+	sort.Slice(lock.Unmanaged, func(i, j int) bool {
+		return lock.Unmanaged[i].Filename < lock.Unmanaged[j].Filename
+	})
+}
+
+func (lock *LockFile) removeUnmanaged(filename string) {
+	for i := range lock.Unmanaged {
+		if lock.Unmanaged[i].Filename == filename {
+			lock.Unmanaged = append(lock.Unmanaged[:i], lock.Unmanaged[i+1:]...)
+			return
+		}
+	}
+}
+
+// addUnmanaged puts a jar from a file or an address into the mods folder and records it in the lockfile
+func addUnmanaged(source string, configData Config) {
+	modsPath := configData.ModsFolder
+	if !dirExists(modsPath) {
+		err := os.MkdirAll(modsPath, 0755)
+		checkError(err)
+	}
+
+	var filename string
+	var err error
+	if isJarURL(source) {
+		filename, err = downloadUnmanaged(source, modsPath)
+	} else {
+		source, err = filepath.Abs(source)
+		checkError(err)
+		filename, err = copyUnmanaged(source, modsPath)
+	}
+	if err != nil {
+		printError(err)
+		return
+	}
+
+	hash := hashFileSHA512(path.Join(modsPath, filename))
+	storeJar(path.Join(modsPath, filename), hash)
+
+	lock := readLockFile(modsPath)
+	origin := source
+	if !isJarURL(source) {
+		origin = filepath.Base(source)
+	}
+	lock.setUnmanaged(UnmanagedMod{Filename: filename, SHA512: hash, Origin: origin})
+	writeLockFile(modsPath, lock)
+	fmt.Printf("[%sAdded%s] %s %s[unmanaged]%s, gorium won't update it\n", Green, Reset, filename, Yellow, Reset)
+}
+
+// function to download a jar into the mods folder, the file is named after the last part of the address
+func downloadUnmanaged(source string, modsPath string) (string, error) {
+	address, err := url.Parse(source)
+	if err != nil {
+		return "", err
+	}
+	filename := path.Base(address.Path)
+	if !strings.HasSuffix(strings.ToLower(filename), ".jar") {
+		return "", fmt.Errorf("%s doesn't end with a jar file name", source)
+	}
+	if err := checkFilenameFree(modsPath, filename); err != nil {
+		return "", err
+	}
+
+	err = downloadFilesConcurrently(modsPath, []map[string]string{{
+		"url":      source,
+		"filename": filename,
+	}})
+	return filename, err
+}
+
+// function to copy a jar into the mods folder, a jar that's already there is only registered
+func copyUnmanaged(source string, modsPath string) (string, error) {
+	if !dirExists(source) {
+		return "", fmt.Errorf("%s doesn't exist", source)
+	}
+	filename := filepath.Base(source)
+	destination := path.Join(modsPath, filename)
+
+	absoluteMods, err := filepath.Abs(modsPath)
+	if err != nil {
+		return "", err
+	}
+	if filepath.Join(absoluteMods, filename) == source {
+		return filename, nil
+	}
+	if err := checkFilenameFree(modsPath, filename); err != nil {
+		return "", err
+	}
+
+	temp := path.Join(modsPath, "."+filename+".part")
+	if err := copyFile(source, temp); err != nil {
+		_ = os.Remove(temp)
+		return "", err
+	}
+	return filename, os.Rename(temp, destination)
+}
+
+// function to make sure adding a jar doesn't replace a different mod of the same name
+func checkFilenameFree(modsPath string, filename string) error {
+	if filename == LockFileName || strings.HasPrefix(filename, ".") {
+		return fmt.Errorf("%s can't be used as a mod file name", filename)
+	}
+	if !dirExists(path.Join(modsPath, filename)) {
+		return nil
+	}
+	lock := readLockFile(modsPath)
+	if lock.findUnmanaged(filename) != nil {
+		return nil
+	}
+	return fmt.Errorf("%s is already in the mods folder, remove it first", filename)
+}
+
+// restoreUnmanaged puts a missing unmanaged jar back from the jar cache or its address, jars copied from
+// a file can only come from the jar cache
+func restoreUnmanaged(modsPath string, mod UnmanagedMod) error {
+	filePath := path.Join(modsPath, mod.Filename)
+	if _, ok := restoreJar(mod.SHA512, filePath); ok {
+		return nil
+	}
+	if isJarURL(mod.Origin) {
+		return downloadFilesConcurrently(modsPath, []map[string]string{{
+			"url":      mod.Origin,
+			"filename": mod.Filename,
+			"sha512":   mod.SHA512,
+		}})
+	}
+	return fmt.Errorf("%s isn't in the jar cache, add %s again with gorium add", mod.Filename, mod.Origin)
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+)
+
+func TestAddLocalJar(t *testing.T) {
+	modsPath := newTestProfile(t, "")
+	jar := filepath.Join(t.TempDir(), "custom-1.0.jar")
+	if err := os.WriteFile(jar, []byte("a jar no provider knows"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	runGorium(t, "add", jar)
+
+	expectJars(t, modsPath, "custom-1.0.jar")
+	lock := readLockFile(modsPath)
+	mod := lock.findUnmanaged("custom-1.0.jar")
+	if mod == nil {
+		t.Fatal("custom-1.0.jar isn't in the lockfile")
+	}
+	// the lockfile is shared, so paths on this computer stay out of it
+	if mod.Origin != "custom-1.0.jar" {
+		t.Fatalf("origin is %q, want only the file name", mod.Origin)
+	}
+
+	// the copy in the jar cache is enough to bring it back, even once the file it came from is gone
+	if err := os.Remove(jar); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(path.Join(modsPath, "custom-1.0.jar")); err != nil {
+		t.Fatal(err)
+	}
+	runGorium(t, "sync")
+	expectJars(t, modsPath, "custom-1.0.jar")
+}
@@ -0,0 +1,255 @@
+package modrinth
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// ErrOffline is returned for requests a Cache in offline mode has no stored response for
+var ErrOffline = errors.New("not available offline")
+
+// CacheStatusHeader is set on responses served by a Cache: "hit" when the stored copy was fresh,
+// "revalidated" when the server answered 304 Not Modified, "stale" when the server couldn't be
+// reached and "offline" when the cache is in offline mode
+const CacheStatusHeader = "X-Gorium-Cache"
+
+// Cache is an http.RoundTripper that keeps successful GET responses on disk. Entries younger than
+// TTL are served without a request, older ones are revalidated with If-None-Match and
+// If-Modified-Since. If the server can't be reached, any stored copy is used no matter how old.
+// Use it as the Transport of a client's HTTPClient.
+type Cache struct {
+	Dir string
+	TTL time.Duration
+	// Transport sends the requests that can't be answered from the cache, http.DefaultTransport if nil
+	Transport http.RoundTripper
+	// OnStale is called when a request failed and a stale copy is used instead
+	OnStale func(requestURL string, err error)
+
+	offline atomic.Bool
+}
+
+// SetOffline switches offline mode, in which nothing is sent and every stored copy counts as fresh
+func (cache *Cache) SetOffline(offline bool) {
+	cache.offline.Store(offline)
+}
+
+func (cache *Cache) IsOffline() bool {
+	return cache.offline.Load()
+}
+
+// cacheEntry is what's stored on disk for a response
+type cacheEntry struct {
+	URL          string      `json:"url"`
+	StatusCode   int         `json:"status_code"`
+	Header       http.Header `json:"header"`
+	Body         []byte      `json:"body"`
+	Stored       time.Time   `json:"stored"`
+	ETag         string      `json:"etag,omitempty"`
+	LastModified string      `json:"last_modified,omitempty"`
+}
+
+// NewCache returns a cache storing responses in dir
+func NewCache(dir string, ttl time.Duration) *Cache {
+	return &Cache{Dir: dir, TTL: ttl}
+}
+
+func (cache *Cache) transport() http.RoundTripper {
+	if cache.Transport == nil {
+		return http.DefaultTransport
+	}
+	return cache.Transport
+}
+
+// cacheable checks if a request may be answered from or stored in the cache
+func cacheable(req *http.Request) bool {
+	return req.Method == http.MethodGet && req.Header.Get("Range") == "" && !strings.Contains(req.Header.Get("Cache-Control"), "no-cache")
+}
+
+// key gets the name of the entry of a request, the credentials are part of it so responses
+// for different tokens don't mix
+func (cache *Cache) key(req *http.Request) string {
+	sum := sha256.Sum256([]byte(req.URL.String() + "\x00" + req.Header.Get("Authorization")))
+	return hex.EncodeToString(sum[:])
+}
+
+func (cache *Cache) entryPath(req *http.Request) string {
+	key := cache.key(req)
+	return filepath.Join(cache.Dir, key[:2], key+".json")
+}
+
+func (cache *Cache) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !cacheable(req) {
+		if cache.IsOffline() {
+			return nil, ErrOffline
+		}
+		return cache.transport().RoundTrip(req)
+	}
+
+	entryPath := cache.entryPath(req)
+	entry := readCacheEntry(entryPath)
+	if cache.IsOffline() {
+		if entry == nil {
+			return nil, ErrOffline
+		}
+		return entry.response(req, "offline"), nil
+	}
+	if entry != nil && time.Since(entry.Stored) < cache.TTL {
+		return entry.response(req, "hit"), nil
+	}
+
+	if entry != nil {
+		req = req.Clone(req.Context())
+		if entry.ETag != "" {
+			req.Header.Set("If-None-Match", entry.ETag)
+		}
+		if entry.LastModified != "" {
+			req.Header.Set("If-Modified-Since", entry.LastModified)
+		}
+	}
+
+	resp, err := cache.transport().RoundTrip(req)
+	if err != nil {
+		if entry == nil || req.Context().Err() != nil {
+			return nil, err
+		}
+		if cache.OnStale != nil {
+			cache.OnStale(req.URL.String(), err)
+		}
+		return entry.response(req, "stale"), nil
+	}
+
+	if resp.StatusCode == http.StatusNotModified && entry != nil {
+		_ = resp.Body.Close()
+		entry.Stored = time.Now()
+		for _, name := range []string{"ETag", "Last-Modified", "Cache-Control", "Date"} {
+			if value := resp.Header.Get(name); value != "" {
+				entry.Header.Set(name, value)
+			}
+		}
+		_ = writeCacheEntry(entryPath, entry)
+		return entry.response(req, "revalidated"), nil
+	}
+
+	if resp.StatusCode != http.StatusOK || strings.Contains(resp.Header.Get("Cache-Control"), "no-store") {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	_ = writeCacheEntry(entryPath, &cacheEntry{
+		URL:          req.URL.String(),
+		StatusCode:   resp.StatusCode,
+		Header:       resp.Header.Clone(),
+		Body:         body,
+		Stored:       time.Now(),
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	})
+	return resp, nil
+}
+
+// response makes an HTTP response out of the entry
+func (entry *cacheEntry) response(req *http.Request, status string) *http.Response {
+	header := entry.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	header.Set(CacheStatusHeader, status)
+	return &http.Response{
+		Status:        http.StatusText(entry.StatusCode),
+		StatusCode:    entry.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(entry.Body)),
+		ContentLength: int64(len(entry.Body)),
+		Request:       req,
+	}
+}
+
+func readCacheEntry(entryPath string) *cacheEntry {
+	data, err := os.ReadFile(entryPath)
+	if err != nil {
+		return nil
+	}
+	var entry cacheEntry
+	if json.Unmarshal(data, &entry) != nil {
+		return nil
+	}
+	return &entry
+}
+
+// writeCacheEntry stores an entry through a temporary file, so readers never see half of it
+func writeCacheEntry(entryPath string, entry *cacheEntry) error {
+	if err := os.MkdirAll(filepath.Dir(entryPath), 0755); err != nil {
+		return err
+	}
+	temp, err := os.CreateTemp(filepath.Dir(entryPath), ".entry-*")
+	if err != nil {
+		return err
+	}
+	writer := bufio.NewWriter(temp)
+	err = json.NewEncoder(writer).Encode(entry)
+	if err == nil {
+		err = writer.Flush()
+	}
+	if closeErr := temp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(temp.Name())
+		return err
+	}
+	return os.Rename(temp.Name(), entryPath)
+}
+
+// Clear removes every entry of the cache
+func (cache *Cache) Clear() error {
+	err := os.RemoveAll(cache.Dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// Size returns the number of entries and the bytes they take on disk
+func (cache *Cache) Size() (int, int64, error) {
+	var entries int
+	var size int64
+	err := filepath.WalkDir(cache.Dir, func(entryPath string, dirEntry os.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if dirEntry.IsDir() || filepath.Ext(entryPath) != ".json" {
+			return nil
+		}
+		info, err := dirEntry.Info()
+		if err != nil {
+			return err
+		}
+		entries++
+		size += info.Size()
+		return nil
+	})
+	return entries, size, err
+}
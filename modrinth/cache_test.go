@@ -0,0 +1,77 @@
+package modrinth
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// cacheTestServer answers with a body and an ETag, and with 304 to requests that send that ETag back
+func cacheTestServer(t *testing.T, requests *atomic.Int32) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("ETag", `"v1"`)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":"AANobbMI"}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// function to send a GET through the cache and return the body and how the cache answered it
+func cachedGet(t *testing.T, cache *Cache, address string) (string, string) {
+	t.Helper()
+	resp, err := (&http.Client{Transport: cache}).Get(address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(body), resp.Header.Get(CacheStatusHeader)
+}
+
+func TestCacheRevalidation(t *testing.T) {
+	var requests atomic.Int32
+	server := cacheTestServer(t, &requests)
+	cache := NewCache(t.TempDir(), 0)
+
+	if body, status := cachedGet(t, cache, server.URL+"/project/sodium"); body != `{"id":"AANobbMI"}` || status != "" {
+		t.Fatalf("first request got %q (%q)", body, status)
+	}
+	// the server only answers 304, the body comes from the cache
+	body, status := cachedGet(t, cache, server.URL+"/project/sodium")
+	if body != `{"id":"AANobbMI"}` || status != "revalidated" {
+		t.Fatalf("revalidation got %q (%q)", body, status)
+	}
+	if requests.Load() != 2 {
+		t.Fatalf("sent %d requests, want 2", requests.Load())
+	}
+}
+
+func TestCacheTTL(t *testing.T) {
+	var requests atomic.Int32
+	server := cacheTestServer(t, &requests)
+	cache := NewCache(t.TempDir(), 100*time.Millisecond)
+
+	cachedGet(t, cache, server.URL+"/project/sodium")
+	if _, status := cachedGet(t, cache, server.URL+"/project/sodium"); status != "hit" || requests.Load() != 1 {
+		t.Fatalf("a fresh entry got %q after %d requests", status, requests.Load())
+	}
+
+	time.Sleep(150 * time.Millisecond)
+	if _, status := cachedGet(t, cache, server.URL+"/project/sodium"); status != "revalidated" || requests.Load() != 2 {
+		t.Fatalf("an expired entry got %q after %d requests", status, requests.Load())
+	}
+}
@@ -0,0 +1,279 @@
+package modrinth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultBaseURL is the address of Modrinth's v2 API
+const DefaultBaseURL = "https://api.modrinth.com/v2"
+
+// Retry settings used when the client doesn't set its own
+const (
+	DefaultAttempts = 5
+	DefaultTimeout  = 30 * time.Second
+	baseBackoff     = 500 * time.Millisecond
+	maxBackoff      = 30 * time.Second
+)
+
+// Client talks to the Modrinth API. Idempotent requests are retried with exponential backoff
+// on network errors, 429 and 5xx, and every request waits for the rate limit window if
+// Modrinth says it's used up. A Client is safe for concurrent use.
+type Client struct {
+	// BaseURL is the API root without a trailing slash, DefaultBaseURL if empty
+	BaseURL string
+	// UserAgent is sent with every request, Modrinth asks for one that identifies the app
+	UserAgent string
+	// Token is a personal access token for private and unlisted projects. It's only sent to
+	// Modrinth's own hosts, never to a mirror or test server set as BaseURL.
+	Token string
+	// HTTPClient sends the requests, a client with DefaultTimeout is used if nil
+	HTTPClient *http.Client
+	// Attempts is how many times an idempotent request is tried, DefaultAttempts if zero
+	Attempts int
+	// OnRateLimit is called before the client waits for the rate limit window to reset
+	OnRateLimit func(wait time.Duration)
+
+	rateLimit struct {
+		sync.Mutex
+		until time.Time
+	}
+}
+
+var defaultHTTPClient = &http.Client{Timeout: DefaultTimeout}
+
+// NewClient returns a client for the public Modrinth API
+func NewClient(userAgent string) *Client {
+	return &Client{BaseURL: DefaultBaseURL, UserAgent: userAgent}
+}
+
+func (c *Client) baseURL() string {
+	if c.BaseURL == "" {
+		return DefaultBaseURL
+	}
+	return strings.TrimSuffix(c.BaseURL, "/")
+}
+
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient == nil {
+		return defaultHTTPClient
+	}
+	return c.HTTPClient
+}
+
+// get sends a GET request and decodes the JSON response into out
+func (c *Client) get(ctx context.Context, path string, query url.Values, out any) error {
+	return c.do(ctx, http.MethodGet, path, query, nil, out)
+}
+
+// post sends the JSON encoding of in and decodes the JSON response into out
+func (c *Client) post(ctx context.Context, path string, query url.Values, in any, out any) error {
+	contents, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	return c.do(ctx, http.MethodPost, path, query, contents, out)
+}
+
+func (c *Client) do(ctx context.Context, method string, path string, query url.Values, contents []byte, out any) error {
+	requestURL := c.baseURL() + path
+	if len(query) > 0 {
+		requestURL += "?" + query.Encode()
+	}
+
+	attempts := 1
+	if isIdempotent(method, path) {
+		attempts = c.Attempts
+		if attempts <= 0 {
+			attempts = DefaultAttempts
+		}
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			if err := sleep(ctx, retryDelay(attempt, lastErr)); err != nil {
+				return err
+			}
+		}
+		if err := c.waitForRateLimit(ctx); err != nil {
+			return err
+		}
+
+		body, retry, err := c.send(ctx, method, requestURL, contents)
+		if err == nil {
+			if out == nil {
+				return nil
+			}
+			if err := json.Unmarshal(body, out); err != nil {
+				return fmt.Errorf("can't decode response of %s %s: %w", method, requestURL, err)
+			}
+			return nil
+		}
+		lastErr = err
+		if !retry || ctx.Err() != nil {
+			break
+		}
+	}
+	return lastErr
+}
+
+// send sends a request once, returns true along with an error if it's worth retrying
+func (c *Client) send(ctx context.Context, method string, requestURL string, contents []byte) ([]byte, bool, error) {
+	var reader io.Reader
+	if contents != nil {
+		reader = bytes.NewReader(contents)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, requestURL, reader)
+	if err != nil {
+		return nil, false, err
+	}
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+	if c.Token != "" && IsModrinthHost(req.URL) {
+		req.Header.Set("Authorization", c.Token)
+	}
+	req.Header.Set("Accept", "application/json")
+	if contents != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient().Do(req)
+	if errors.Is(err, ErrOffline) {
+		return nil, false, err
+	}
+	if err != nil {
+		return nil, true, fmt.Errorf("can't reach Modrinth: %w", err)
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+
+	c.updateRateLimit(resp)
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, true, fmt.Errorf("error reading response from Modrinth: %w", err)
+	}
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return body, false, nil
+	}
+
+	apiErr := newAPIError(method, requestURL, resp, body)
+	retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return nil, retry, apiErr
+}
+
+// IsModrinthHost checks if a URL points to modrinth.com or one of its subdomains, like the API and the CDN
+func IsModrinthHost(u *url.URL) bool {
+	if u.Scheme != "https" {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	return host == "modrinth.com" || strings.HasSuffix(host, ".modrinth.com")
+}
+
+// isIdempotent checks if a request can be sent again without side effects.
+// Modrinth's hash lookups are POST requests but they only read data.
+func isIdempotent(method string, path string) bool {
+	if method == http.MethodGet || method == http.MethodHead {
+		return true
+	}
+	if method != http.MethodPost {
+		return false
+	}
+	return strings.HasSuffix(path, "/version_files") || strings.HasSuffix(path, "/version_files/update")
+}
+
+// updateRateLimit remembers when the rate limit resets if no requests are left in the current window
+func (c *Client) updateRateLimit(resp *http.Response) {
+	remaining := resp.Header.Get("X-Ratelimit-Remaining")
+	if remaining == "" {
+		return
+	}
+	if left, err := strconv.Atoi(remaining); err != nil || left > 0 {
+		return
+	}
+	reset := parseSeconds(resp.Header.Get("X-Ratelimit-Reset"))
+	if reset == 0 {
+		return
+	}
+
+	c.rateLimit.Lock()
+	defer c.rateLimit.Unlock()
+	if until := time.Now().Add(reset); until.After(c.rateLimit.until) {
+		c.rateLimit.until = until
+	}
+}
+
+func (c *Client) waitForRateLimit(ctx context.Context) error {
+	c.rateLimit.Lock()
+	wait := time.Until(c.rateLimit.until)
+	c.rateLimit.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+	if c.OnRateLimit != nil {
+		c.OnRateLimit(wait)
+	}
+	return sleep(ctx, wait)
+}
+
+// retryDelay gets the delay before the next attempt, the server's Retry-After wins over the backoff
+func retryDelay(attempt int, lastErr error) time.Duration {
+	var apiErr *APIError
+	if errors.As(lastErr, &apiErr) && apiErr.RetryAfter > 0 {
+		return apiErr.RetryAfter
+	}
+
+	delay := baseBackoff << (attempt - 1)
+	if delay > maxBackoff {
+		delay = maxBackoff
+	}
+	// some jitter so parallel requests don't retry in lockstep
+	return delay/2 + rand.N(delay/2+1)
+}
+
+func sleep(ctx context.Context, duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// parseSeconds parses a header holding a number of seconds
+func parseSeconds(value string) time.Duration {
+	seconds, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// jsonArray encodes ids the way Modrinth expects lists in query strings, like ["a","b"]
+func jsonArray(values []string) string {
+	if values == nil {
+		values = []string{}
+	}
+	encoded, _ := json.Marshal(values)
+	return string(encoded)
+}
@@ -0,0 +1,65 @@
+package modrinth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryAfterRateLimit(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":"AANobbMI","slug":"sodium"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("gorium-test")
+	client.BaseURL = server.URL
+	start := time.Now()
+	project, err := client.GetProject(context.Background(), "sodium")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the backoff alone would have retried sooner
+	if waited := time.Since(start); waited < time.Second {
+		t.Fatalf("retried after %s, Retry-After asked for 1s", waited)
+	}
+	if project.ID != "AANobbMI" || requests.Load() != 2 {
+		t.Fatalf("got %+v after %d requests", project, requests.Load())
+	}
+}
+
+func TestServerErrorIsTyped(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+		_, _ = w.Write([]byte(`{"error":"bad_gateway","description":"try again"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("gorium-test")
+	client.BaseURL = server.URL
+	client.Attempts = 2
+	_, err := client.GetProject(context.Background(), "sodium")
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("got %v, want an APIError", err)
+	}
+	if apiErr.StatusCode != http.StatusBadGateway || apiErr.ErrorName != "bad_gateway" || apiErr.Description != "try again" {
+		t.Fatalf("got %+v", apiErr)
+	}
+	if requests.Load() != 2 {
+		t.Fatalf("sent %d requests, want 2", requests.Load())
+	}
+}
@@ -0,0 +1,28 @@
+// Command fake-modrinth serves a fixtures directory as a fake Modrinth API, so gorium can be
+// run against it without the network:
+//
+//	go run ./cmd/fake-modrinth -fixtures modrinthtest/testdata/basic -addr 127.0.0.1:8080
+//	GORIUM_MODRINTH_API=http://127.0.0.1:8080 gorium upgrade
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+
+	"gorium/modrinth/modrinthtest"
+)
+
+func main() {
+	fixturesDir := flag.String("fixtures", "modrinthtest/testdata/basic", "directory with the fixtures to serve")
+	addr := flag.String("addr", "127.0.0.1:8080", "address to listen on")
+	flag.Parse()
+
+	fixtures, err := modrinthtest.LoadFixtures(*fixturesDir)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	log.Printf("Serving %d projects and %d versions on http://%s", len(fixtures.Projects), len(fixtures.Versions), *addr)
+	log.Fatal(http.ListenAndServe(*addr, modrinthtest.NewHandler(fixtures)))
+}
@@ -0,0 +1,7 @@
+// Package modrinth is a client for the Modrinth v2 API (https://docs.modrinth.com/api/).
+//
+//	client := modrinth.NewClient("owner/app/1.0")
+//	project, err := client.GetProject(context.Background(), "sodium")
+//
+// Errors returned for non-2xx responses are *APIError.
+package modrinth
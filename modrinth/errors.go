@@ -0,0 +1,71 @@
+package modrinth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// APIError is returned when Modrinth answers with a non-2xx status
+type APIError struct {
+	Method      string
+	URL         string
+	StatusCode  int
+	Status      string
+	ErrorName   string // "error" field of Modrinth's error body, like "not_found"
+	Description string
+	RetryAfter  time.Duration // set for 429 responses
+}
+
+func newAPIError(method string, requestURL string, resp *http.Response, body []byte) *APIError {
+	apiErr := &APIError{
+		Method:     method,
+		URL:        requestURL,
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+	}
+	var errorBody struct {
+		Error       string `json:"error"`
+		Description string `json:"description"`
+	}
+	if json.Unmarshal(body, &errorBody) == nil {
+		apiErr.ErrorName = errorBody.Error
+		apiErr.Description = errorBody.Description
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		apiErr.RetryAfter = parseSeconds(resp.Header.Get("Retry-After"))
+		if apiErr.RetryAfter == 0 {
+			apiErr.RetryAfter = parseSeconds(resp.Header.Get("X-Ratelimit-Reset"))
+		}
+	}
+	return apiErr
+}
+
+func (e *APIError) Error() string {
+	message := fmt.Sprintf("Modrinth returned %s for %s %s", e.Status, e.Method, e.URL)
+	if e.Description != "" {
+		message += ": " + e.Description
+	}
+	return message
+}
+
+func (e *APIError) NotFound() bool {
+	return e.StatusCode == http.StatusNotFound
+}
+
+// Unauthorized reports a missing, invalid or expired token, or one without the needed scopes
+func (e *APIError) Unauthorized() bool {
+	return e.StatusCode == http.StatusUnauthorized
+}
+
+func (e *APIError) RateLimited() bool {
+	return e.StatusCode == http.StatusTooManyRequests
+}
+
+// IsNotFound reports whether err is an APIError for a 404 response
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.NotFound()
+}
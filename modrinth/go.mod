@@ -0,0 +1,3 @@
+module gorium/modrinth
+
+go 1.23.2
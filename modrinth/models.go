@@ -0,0 +1,221 @@
+package modrinth
+
+import "time"
+
+// Project is a mod, modpack, resource pack or other project hosted on Modrinth
+type Project struct {
+	ID                   string            `json:"id"`
+	Slug                 string            `json:"slug"`
+	Title                string            `json:"title"`
+	Description          string            `json:"description"`
+	Body                 string            `json:"body"`
+	Categories           []string          `json:"categories"`
+	AdditionalCategories []string          `json:"additional_categories"`
+	ClientSide           string            `json:"client_side"`
+	ServerSide           string            `json:"server_side"`
+	Status               string            `json:"status"`
+	RequestedStatus      string            `json:"requested_status,omitempty"`
+	ProjectType          string            `json:"project_type"`
+	Team                 string            `json:"team"`
+	Downloads            int               `json:"downloads"`
+	Followers            int               `json:"followers"`
+	IconURL              string            `json:"icon_url,omitempty"`
+	Color                int               `json:"color,omitempty"`
+	ThreadID             string            `json:"thread_id,omitempty"`
+	MonetizationStatus   string            `json:"monetization_status,omitempty"`
+	IssuesURL            string            `json:"issues_url,omitempty"`
+	SourceURL            string            `json:"source_url,omitempty"`
+	WikiURL              string            `json:"wiki_url,omitempty"`
+	DiscordURL           string            `json:"discord_url,omitempty"`
+	DonationURLs         []DonationURL     `json:"donation_urls"`
+	License              License           `json:"license"`
+	ModeratorMessage     *ModeratorMessage `json:"moderator_message,omitempty"`
+	Published            time.Time         `json:"published"`
+	Updated              time.Time         `json:"updated"`
+	Approved             *time.Time        `json:"approved,omitempty"`
+	Queued               *time.Time        `json:"queued,omitempty"`
+	Versions             []string          `json:"versions"`
+	GameVersions         []string          `json:"game_versions"`
+	Loaders              []string          `json:"loaders"`
+	Gallery              []GalleryImage    `json:"gallery"`
+}
+
+type DonationURL struct {
+	ID       string `json:"id"`
+	Platform string `json:"platform"`
+	URL      string `json:"url"`
+}
+
+type License struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	URL  string `json:"url,omitempty"`
+}
+
+type ModeratorMessage struct {
+	Message string `json:"message"`
+	Body    string `json:"body,omitempty"`
+}
+
+type GalleryImage struct {
+	URL         string    `json:"url"`
+	Featured    bool      `json:"featured"`
+	Title       string    `json:"title,omitempty"`
+	Description string    `json:"description,omitempty"`
+	Created     time.Time `json:"created"`
+	Ordering    int       `json:"ordering"`
+}
+
+// ProjectDependencies is everything a project depends on, as returned by /project/{id}/dependencies
+type ProjectDependencies struct {
+	Projects []Project `json:"projects"`
+	Versions []Version `json:"versions"`
+}
+
+// Version is a single release of a project
+type Version struct {
+	ID              string       `json:"id"`
+	ProjectID       string       `json:"project_id"`
+	AuthorID        string       `json:"author_id"`
+	Name            string       `json:"name"`
+	VersionNumber   string       `json:"version_number"`
+	Changelog       string       `json:"changelog,omitempty"`
+	Dependencies    []Dependency `json:"dependencies"`
+	GameVersions    []string     `json:"game_versions"`
+	VersionType     string       `json:"version_type"`
+	Loaders         []string     `json:"loaders"`
+	Featured        bool         `json:"featured"`
+	Status          string       `json:"status,omitempty"`
+	RequestedStatus string       `json:"requested_status,omitempty"`
+	DatePublished   time.Time    `json:"date_published"`
+	Downloads       int          `json:"downloads"`
+	Files           []File       `json:"files"`
+}
+
+// Version types, also used as release channels
+const (
+	VersionTypeRelease = "release"
+	VersionTypeBeta    = "beta"
+	VersionTypeAlpha   = "alpha"
+)
+
+// Dependency types of a version
+const (
+	DependencyRequired     = "required"
+	DependencyOptional     = "optional"
+	DependencyIncompatible = "incompatible"
+	DependencyEmbedded     = "embedded"
+)
+
+type Dependency struct {
+	VersionID      string `json:"version_id,omitempty"`
+	ProjectID      string `json:"project_id,omitempty"`
+	FileName       string `json:"file_name,omitempty"`
+	DependencyType string `json:"dependency_type"`
+}
+
+type File struct {
+	Hashes   Hashes `json:"hashes"`
+	URL      string `json:"url"`
+	Filename string `json:"filename"`
+	Primary  bool   `json:"primary"`
+	Size     int64  `json:"size"`
+	FileType string `json:"file_type,omitempty"`
+}
+
+type Hashes struct {
+	SHA1   string `json:"sha1"`
+	SHA512 string `json:"sha512"`
+	// SHA256 isn't sent by Modrinth, sources like GitHub only have this one
+	SHA256 string `json:"sha256,omitempty"`
+}
+
+// PrimaryFile returns the file marked as primary, or the first one if none is
+func (version *Version) PrimaryFile() File {
+	for _, file := range version.Files {
+		if file.Primary {
+			return file
+		}
+	}
+	if len(version.Files) == 0 {
+		return File{}
+	}
+	return version.Files[0]
+}
+
+// SearchResult is a page of search results
+type SearchResult struct {
+	Hits      []SearchHit `json:"hits"`
+	Offset    int         `json:"offset"`
+	Limit     int         `json:"limit"`
+	TotalHits int         `json:"total_hits"`
+}
+
+type SearchHit struct {
+	ProjectID          string    `json:"project_id"`
+	Slug               string    `json:"slug"`
+	Title              string    `json:"title"`
+	Description        string    `json:"description"`
+	Categories         []string  `json:"categories"`
+	DisplayCategories  []string  `json:"display_categories"`
+	ClientSide         string    `json:"client_side"`
+	ServerSide         string    `json:"server_side"`
+	ProjectType        string    `json:"project_type"`
+	Downloads          int       `json:"downloads"`
+	Follows            int       `json:"follows"`
+	IconURL            string    `json:"icon_url,omitempty"`
+	Color              int       `json:"color,omitempty"`
+	ThreadID           string    `json:"thread_id,omitempty"`
+	MonetizationStatus string    `json:"monetization_status,omitempty"`
+	Author             string    `json:"author"`
+	Versions           []string  `json:"versions"`
+	DateCreated        time.Time `json:"date_created"`
+	DateModified       time.Time `json:"date_modified"`
+	LatestVersion      string    `json:"latest_version,omitempty"`
+	License            string    `json:"license"`
+	Gallery            []string  `json:"gallery"`
+	FeaturedGallery    string    `json:"featured_gallery,omitempty"`
+}
+
+// TeamMember is a member of the team that owns a project
+type TeamMember struct {
+	TeamID       string  `json:"team_id"`
+	User         User    `json:"user"`
+	Role         string  `json:"role"`
+	Permissions  *int64  `json:"permissions,omitempty"`
+	Accepted     bool    `json:"accepted"`
+	PayoutsSplit float64 `json:"payouts_split,omitempty"`
+	Ordering     int     `json:"ordering"`
+}
+
+type User struct {
+	ID        string    `json:"id"`
+	Username  string    `json:"username"`
+	Name      string    `json:"name,omitempty"`
+	Bio       string    `json:"bio,omitempty"`
+	AvatarURL string    `json:"avatar_url"`
+	Created   time.Time `json:"created"`
+	Role      string    `json:"role"`
+	Badges    int64     `json:"badges"`
+}
+
+// GameVersionTag is a Minecraft version known to Modrinth
+type GameVersionTag struct {
+	Version     string    `json:"version"`
+	VersionType string    `json:"version_type"`
+	Date        time.Time `json:"date"`
+	Major       bool      `json:"major"`
+}
+
+type LoaderTag struct {
+	Icon                  string   `json:"icon"`
+	Name                  string   `json:"name"`
+	SupportedProjectTypes []string `json:"supported_project_types"`
+}
+
+type CategoryTag struct {
+	Icon        string `json:"icon"`
+	Name        string `json:"name"`
+	ProjectType string `json:"project_type"`
+	Header      string `json:"header"`
+}
@@ -0,0 +1,101 @@
+package modrinthtest
+
+import (
+	"crypto/sha1"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gorium/modrinth"
+)
+
+// Fixtures is the data a fake server answers with
+type Fixtures struct {
+	Projects     []modrinth.Project
+	Versions     []modrinth.Version
+	GameVersions []modrinth.GameVersionTag
+	Loaders      []modrinth.LoaderTag
+	Categories   []modrinth.CategoryTag
+	Members      map[string][]modrinth.TeamMember // keyed by team ID
+	// Files are the contents of version files, keyed by filename. Files of versions that are
+	// listed here get their hashes filled in and their URL pointed at the fake server.
+	Files map[string][]byte
+}
+
+// LoadFixtures reads fixtures from a directory:
+//
+//	projects.json      array of projects
+//	versions.json      array of versions
+//	game_versions.json array of game version tags (optional)
+//	loaders.json       array of loader tags (optional)
+//	categories.json    array of category tags (optional)
+//	members.json       object of team ID to array of members (optional)
+//	files/             contents of version files, by filename (optional)
+func LoadFixtures(dir string) (*Fixtures, error) {
+	fixtures := &Fixtures{Files: map[string][]byte{}}
+
+	sources := []struct {
+		name     string
+		into     any
+		optional bool
+	}{
+		{"projects.json", &fixtures.Projects, false},
+		{"versions.json", &fixtures.Versions, false},
+		{"game_versions.json", &fixtures.GameVersions, true},
+		{"loaders.json", &fixtures.Loaders, true},
+		{"categories.json", &fixtures.Categories, true},
+		{"members.json", &fixtures.Members, true},
+	}
+	for _, source := range sources {
+		data, err := os.ReadFile(filepath.Join(dir, source.name))
+		if errors.Is(err, os.ErrNotExist) && source.optional {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, source.into); err != nil {
+			return nil, fmt.Errorf("%s: %w", source.name, err)
+		}
+	}
+
+	entries, err := os.ReadDir(filepath.Join(dir, "files"))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, "files", entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		fixtures.Files[entry.Name()] = data
+	}
+	return fixtures, nil
+}
+
+// fillFiles sets the hashes and size of version files that have contents in Files
+func (fixtures *Fixtures) fillFiles() {
+	for i := range fixtures.Versions {
+		for j := range fixtures.Versions[i].Files {
+			file := &fixtures.Versions[i].Files[j]
+			data, ok := fixtures.Files[file.Filename]
+			if !ok {
+				continue
+			}
+			sha1Sum := sha1.Sum(data)
+			sha512Sum := sha512.Sum512(data)
+			file.Hashes = modrinth.Hashes{
+				SHA1:   hex.EncodeToString(sha1Sum[:]),
+				SHA512: hex.EncodeToString(sha512Sum[:]),
+			}
+			file.Size = int64(len(data))
+		}
+	}
+}
@@ -0,0 +1,501 @@
+// Package modrinthtest provides a fake Modrinth API server driven by fixtures, so code using the
+// modrinth package can be run against a known set of projects without the network.
+//
+//	fixtures, _ := modrinthtest.LoadFixtures("testdata/basic")
+//	server := modrinthtest.NewServer(fixtures)
+//	defer server.Close()
+//	client := &modrinth.Client{BaseURL: server.URL}
+//
+// Version files whose contents are in the fixtures are served under /data/, with Range support.
+package modrinthtest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gorium/modrinth"
+)
+
+// Server answers the Modrinth endpoints from fixtures
+type Server struct {
+	fixtures *Fixtures
+	mux      *http.ServeMux
+
+	mutex    sync.Mutex
+	requests []string
+}
+
+// NewHandler returns a fake Modrinth API serving the fixtures
+func NewHandler(fixtures *Fixtures) *Server {
+	fixtures.fillFiles()
+	server := &Server{fixtures: fixtures, mux: http.NewServeMux()}
+
+	server.mux.HandleFunc("GET /project/{id}", server.getProject)
+	server.mux.HandleFunc("GET /projects", server.getProjects)
+	server.mux.HandleFunc("GET /project/{id}/version", server.getProjectVersions)
+	server.mux.HandleFunc("GET /project/{id}/version/{version}", server.getProjectVersion)
+	server.mux.HandleFunc("GET /project/{id}/dependencies", server.getProjectDependencies)
+	server.mux.HandleFunc("GET /project/{id}/members", server.getProjectMembers)
+	server.mux.HandleFunc("GET /team/{id}/members", server.getTeamMembers)
+	server.mux.HandleFunc("GET /version/{id}", server.getVersion)
+	server.mux.HandleFunc("GET /versions", server.getVersions)
+	server.mux.HandleFunc("GET /version_file/{hash}", server.getVersionFromHash)
+	server.mux.HandleFunc("POST /version_file/{hash}/update", server.getLatestVersionFromHash)
+	server.mux.HandleFunc("POST /version_files", server.getVersionsFromHashes)
+	server.mux.HandleFunc("POST /version_files/update", server.getLatestVersionsFromHashes)
+	server.mux.HandleFunc("GET /search", server.search)
+	server.mux.HandleFunc("GET /tag/game_version", server.tag(func() any { return fixtures.GameVersions }))
+	server.mux.HandleFunc("GET /tag/loader", server.tag(func() any { return fixtures.Loaders }))
+	server.mux.HandleFunc("GET /tag/category", server.tag(func() any { return fixtures.Categories }))
+	server.mux.HandleFunc("GET /data/{filename}", server.getFile)
+	return server
+}
+
+// NewServer starts a fake Modrinth API on a local port, its URL can be used as a client's BaseURL
+func NewServer(fixtures *Fixtures) *httptest.Server {
+	return httptest.NewServer(NewHandler(fixtures))
+}
+
+func (server *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	server.mutex.Lock()
+	server.requests = append(server.requests, r.Method+" "+r.URL.Path)
+	server.mutex.Unlock()
+	server.mux.ServeHTTP(w, r)
+}
+
+// Requests returns the method and path of every request served so far
+func (server *Server) Requests() []string {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	return slices.Clone(server.requests)
+}
+
+// writeJSON sends a value with an ETag of its encoding, answering 304 if the client already has it
+func writeJSON(w http.ResponseWriter, r *http.Request, value any) {
+	body, err := json.Marshal(value)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "internal_error", err.Error())
+		return
+	}
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	w.Header().Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(body)
+}
+
+func writeError(w http.ResponseWriter, status int, name string, description string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": name, "description": description})
+}
+
+func notFound(w http.ResponseWriter) {
+	writeError(w, http.StatusNotFound, "not_found", "the requested route does not exist")
+}
+
+// function to decode a list query parameter like ids=["a","b"]
+func queryList(r *http.Request, name string) ([]string, bool) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return nil, false
+	}
+	var list []string
+	if json.Unmarshal([]byte(value), &list) != nil {
+		return nil, false
+	}
+	return list, true
+}
+
+// withURLs points the files of a version at this server if their contents are known
+func (server *Server) withURLs(r *http.Request, version modrinth.Version) modrinth.Version {
+	version.Files = slices.Clone(version.Files)
+	for i, file := range version.Files {
+		if _, ok := server.fixtures.Files[file.Filename]; ok {
+			version.Files[i].URL = "http://" + r.Host + "/data/" + file.Filename
+		}
+	}
+	return version
+}
+
+func (server *Server) findProject(idOrSlug string) *modrinth.Project {
+	for i, project := range server.fixtures.Projects {
+		if project.ID == idOrSlug || strings.EqualFold(project.Slug, idOrSlug) {
+			return &server.fixtures.Projects[i]
+		}
+	}
+	return nil
+}
+
+func (server *Server) findVersion(id string) *modrinth.Version {
+	for i, version := range server.fixtures.Versions {
+		if version.ID == id {
+			return &server.fixtures.Versions[i]
+		}
+	}
+	return nil
+}
+
+func (server *Server) findVersionByHash(hash string, algorithm string) *modrinth.Version {
+	for i, version := range server.fixtures.Versions {
+		for _, file := range version.Files {
+			if (algorithm == modrinth.SHA1 && file.Hashes.SHA1 == hash) || (algorithm != modrinth.SHA1 && file.Hashes.SHA512 == hash) {
+				return &server.fixtures.Versions[i]
+			}
+		}
+	}
+	return nil
+}
+
+// projectVersions returns the versions of a project matching the filter, newest first
+func (server *Server) projectVersions(projectID string, filter modrinth.UpdateFilter) []modrinth.Version {
+	matches := func(values []string, allowed []string) bool {
+		if len(allowed) == 0 {
+			return true
+		}
+		for _, value := range values {
+			if slices.Contains(allowed, value) {
+				return true
+			}
+		}
+		return false
+	}
+
+	var versions []modrinth.Version
+	for _, version := range server.fixtures.Versions {
+		if version.ProjectID != projectID {
+			continue
+		}
+		if matches(version.Loaders, filter.Loaders) && matches(version.GameVersions, filter.GameVersions) && matches([]string{version.VersionType}, filter.VersionTypes) {
+			versions = append(versions, version)
+		}
+	}
+	sort.SliceStable(versions, func(i, j int) bool {
+		return versions[i].DatePublished.After(versions[j].DatePublished)
+	})
+	return versions
+}
+
+func (server *Server) getProject(w http.ResponseWriter, r *http.Request) {
+	project := server.findProject(r.PathValue("id"))
+	if project == nil {
+		notFound(w)
+		return
+	}
+	writeJSON(w, r, project)
+}
+
+func (server *Server) getProjects(w http.ResponseWriter, r *http.Request) {
+	ids, ok := queryList(r, "ids")
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid_input", "ids must be a JSON array")
+		return
+	}
+	projects := []modrinth.Project{}
+	for _, id := range ids {
+		if project := server.findProject(id); project != nil {
+			projects = append(projects, *project)
+		}
+	}
+	writeJSON(w, r, projects)
+}
+
+func (server *Server) getProjectVersions(w http.ResponseWriter, r *http.Request) {
+	project := server.findProject(r.PathValue("id"))
+	if project == nil {
+		notFound(w)
+		return
+	}
+	var filter modrinth.UpdateFilter
+	filter.Loaders, _ = queryList(r, "loaders")
+	filter.GameVersions, _ = queryList(r, "game_versions")
+
+	featured, filterFeatured := r.URL.Query().Get("featured"), r.URL.Query().Has("featured")
+	versions := []modrinth.Version{}
+	for _, version := range server.projectVersions(project.ID, filter) {
+		if filterFeatured && strconv.FormatBool(version.Featured) != featured {
+			continue
+		}
+		versions = append(versions, server.withURLs(r, version))
+	}
+	writeJSON(w, r, versions)
+}
+
+func (server *Server) getProjectVersion(w http.ResponseWriter, r *http.Request) {
+	project := server.findProject(r.PathValue("id"))
+	if project == nil {
+		notFound(w)
+		return
+	}
+	idOrNumber := r.PathValue("version")
+	for _, version := range server.projectVersions(project.ID, modrinth.UpdateFilter{}) {
+		if version.ID == idOrNumber || version.VersionNumber == idOrNumber {
+			writeJSON(w, r, server.withURLs(r, version))
+			return
+		}
+	}
+	notFound(w)
+}
+
+func (server *Server) getProjectDependencies(w http.ResponseWriter, r *http.Request) {
+	project := server.findProject(r.PathValue("id"))
+	if project == nil {
+		notFound(w)
+		return
+	}
+	dependencies := modrinth.ProjectDependencies{Projects: []modrinth.Project{}, Versions: []modrinth.Version{}}
+	seen := map[string]bool{}
+	for _, version := range server.projectVersions(project.ID, modrinth.UpdateFilter{}) {
+		for _, dependency := range version.Dependencies {
+			if dependency.VersionID != "" && !seen[dependency.VersionID] {
+				seen[dependency.VersionID] = true
+				if dependencyVersion := server.findVersion(dependency.VersionID); dependencyVersion != nil {
+					dependencies.Versions = append(dependencies.Versions, server.withURLs(r, *dependencyVersion))
+				}
+			}
+			if dependency.ProjectID != "" && !seen[dependency.ProjectID] {
+				seen[dependency.ProjectID] = true
+				if dependencyProject := server.findProject(dependency.ProjectID); dependencyProject != nil {
+					dependencies.Projects = append(dependencies.Projects, *dependencyProject)
+				}
+			}
+		}
+	}
+	writeJSON(w, r, dependencies)
+}
+
+func (server *Server) getProjectMembers(w http.ResponseWriter, r *http.Request) {
+	project := server.findProject(r.PathValue("id"))
+	if project == nil {
+		notFound(w)
+		return
+	}
+	writeJSON(w, r, server.members(project.Team))
+}
+
+func (server *Server) getTeamMembers(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, r, server.members(r.PathValue("id")))
+}
+
+func (server *Server) members(teamID string) []modrinth.TeamMember {
+	if members, ok := server.fixtures.Members[teamID]; ok {
+		return members
+	}
+	return []modrinth.TeamMember{}
+}
+
+func (server *Server) getVersion(w http.ResponseWriter, r *http.Request) {
+	version := server.findVersion(r.PathValue("id"))
+	if version == nil {
+		notFound(w)
+		return
+	}
+	writeJSON(w, r, server.withURLs(r, *version))
+}
+
+func (server *Server) getVersions(w http.ResponseWriter, r *http.Request) {
+	ids, ok := queryList(r, "ids")
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid_input", "ids must be a JSON array")
+		return
+	}
+	versions := []modrinth.Version{}
+	for _, id := range ids {
+		if version := server.findVersion(id); version != nil {
+			versions = append(versions, server.withURLs(r, *version))
+		}
+	}
+	writeJSON(w, r, versions)
+}
+
+func (server *Server) getVersionFromHash(w http.ResponseWriter, r *http.Request) {
+	version := server.findVersionByHash(r.PathValue("hash"), r.URL.Query().Get("algorithm"))
+	if version == nil {
+		notFound(w)
+		return
+	}
+	writeJSON(w, r, server.withURLs(r, *version))
+}
+
+func (server *Server) getLatestVersionFromHash(w http.ResponseWriter, r *http.Request) {
+	var filter modrinth.UpdateFilter
+	if err := json.NewDecoder(r.Body).Decode(&filter); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_input", err.Error())
+		return
+	}
+	version := server.findVersionByHash(r.PathValue("hash"), r.URL.Query().Get("algorithm"))
+	if version == nil {
+		notFound(w)
+		return
+	}
+	versions := server.projectVersions(version.ProjectID, filter)
+	if len(versions) == 0 {
+		notFound(w)
+		return
+	}
+	writeJSON(w, r, server.withURLs(r, versions[0]))
+}
+
+type hashesRequest struct {
+	Hashes    []string `json:"hashes"`
+	Algorithm string   `json:"algorithm"`
+	modrinth.UpdateFilter
+}
+
+func (server *Server) getVersionsFromHashes(w http.ResponseWriter, r *http.Request) {
+	var request hashesRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_input", err.Error())
+		return
+	}
+	versions := map[string]modrinth.Version{}
+	for _, hash := range request.Hashes {
+		if version := server.findVersionByHash(hash, request.Algorithm); version != nil {
+			versions[hash] = server.withURLs(r, *version)
+		}
+	}
+	writeJSON(w, r, versions)
+}
+
+func (server *Server) getLatestVersionsFromHashes(w http.ResponseWriter, r *http.Request) {
+	var request hashesRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_input", err.Error())
+		return
+	}
+	versions := map[string]modrinth.Version{}
+	for _, hash := range request.Hashes {
+		version := server.findVersionByHash(hash, request.Algorithm)
+		if version == nil {
+			continue
+		}
+		if latest := server.projectVersions(version.ProjectID, request.UpdateFilter); len(latest) > 0 {
+			versions[hash] = server.withURLs(r, latest[0])
+		}
+	}
+	writeJSON(w, r, versions)
+}
+
+func (server *Server) search(w http.ResponseWriter, r *http.Request) {
+	query := strings.ToLower(r.URL.Query().Get("query"))
+	var facets [][]string
+	if value := r.URL.Query().Get("facets"); value != "" {
+		if err := json.Unmarshal([]byte(value), &facets); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_input", "facets must be a JSON array of arrays")
+			return
+		}
+	}
+
+	var hits []modrinth.SearchHit
+	for _, project := range server.fixtures.Projects {
+		if query != "" && !strings.Contains(strings.ToLower(project.Title+" "+project.Slug+" "+project.Description), query) {
+			continue
+		}
+		hit := searchHit(project)
+		if matchesFacets(hit, facets) {
+			hits = append(hits, hit)
+		}
+	}
+
+	if r.URL.Query().Get("index") == modrinth.IndexDownloads {
+		sort.SliceStable(hits, func(i, j int) bool { return hits[i].Downloads > hits[j].Downloads })
+	}
+
+	result := modrinth.SearchResult{Hits: []modrinth.SearchHit{}, Limit: 10, TotalHits: len(hits)}
+	if limit, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && limit > 0 {
+		result.Limit = min(limit, 100)
+	}
+	if offset, err := strconv.Atoi(r.URL.Query().Get("offset")); err == nil && offset > 0 {
+		result.Offset = offset
+	}
+	if result.Offset < len(hits) {
+		result.Hits = hits[result.Offset:min(len(hits), result.Offset+result.Limit)]
+	}
+	writeJSON(w, r, result)
+}
+
+// searchHit makes a search result out of a project, like Modrinth the loaders count as categories
+func searchHit(project modrinth.Project) modrinth.SearchHit {
+	categories := slices.Concat(project.Categories, project.Loaders)
+	return modrinth.SearchHit{
+		ProjectID:         project.ID,
+		Slug:              project.Slug,
+		Title:             project.Title,
+		Description:       project.Description,
+		Categories:        categories,
+		DisplayCategories: categories,
+		ClientSide:        project.ClientSide,
+		ServerSide:        project.ServerSide,
+		ProjectType:       project.ProjectType,
+		Downloads:         project.Downloads,
+		Follows:           project.Followers,
+		IconURL:           project.IconURL,
+		Versions:          project.GameVersions,
+		DateCreated:       project.Published,
+		DateModified:      project.Updated,
+		License:           project.License.ID,
+	}
+}
+
+// matchesFacets checks the hit against facets like [["categories:fabric"],["versions:1.21"]],
+// every outer group has to match and any facet inside a group is enough
+func matchesFacets(hit modrinth.SearchHit, facets [][]string) bool {
+	for _, group := range facets {
+		matched := false
+		for _, facet := range group {
+			key, value, _ := strings.Cut(facet, ":")
+			switch key {
+			case "project_type":
+				matched = hit.ProjectType == value
+			case "categories":
+				matched = slices.Contains(hit.Categories, value)
+			case "versions":
+				matched = slices.Contains(hit.Versions, value)
+			case "project_id":
+				matched = hit.ProjectID == value
+			case "client_side":
+				matched = hit.ClientSide == value
+			case "server_side":
+				matched = hit.ServerSide == value
+			}
+			if matched {
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+func (server *Server) tag(values func() any) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, r, values())
+	}
+}
+
+func (server *Server) getFile(w http.ResponseWriter, r *http.Request) {
+	data, ok := server.fixtures.Files[r.PathValue("filename")]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/java-archive")
+	http.ServeContent(w, r, r.PathValue("filename"), time.Time{}, bytes.NewReader(data))
+}
@@ -0,0 +1,68 @@
+package modrinth
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// GetProject gets a project by its id or slug
+func (c *Client) GetProject(ctx context.Context, idOrSlug string) (*Project, error) {
+	var project Project
+	if err := c.get(ctx, "/project/"+url.PathEscape(idOrSlug), nil, &project); err != nil {
+		return nil, err
+	}
+	return &project, nil
+}
+
+// GetProjects gets several projects at once, unknown ids are left out of the result
+func (c *Client) GetProjects(ctx context.Context, ids []string) ([]Project, error) {
+	var projects []Project
+	query := url.Values{"ids": {jsonArray(ids)}}
+	if err := c.get(ctx, "/projects", query, &projects); err != nil {
+		return nil, err
+	}
+	return projects, nil
+}
+
+// GetProjectDependencies gets every project and version a project depends on
+func (c *Client) GetProjectDependencies(ctx context.Context, idOrSlug string) (*ProjectDependencies, error) {
+	var dependencies ProjectDependencies
+	if err := c.get(ctx, "/project/"+url.PathEscape(idOrSlug)+"/dependencies", nil, &dependencies); err != nil {
+		return nil, err
+	}
+	return &dependencies, nil
+}
+
+// VersionFilter narrows down the versions of a project, empty fields don't filter
+type VersionFilter struct {
+	Loaders      []string
+	GameVersions []string
+	Featured     *bool
+}
+
+func (filter *VersionFilter) query() url.Values {
+	query := url.Values{}
+	if filter == nil {
+		return query
+	}
+	if len(filter.Loaders) > 0 {
+		query.Set("loaders", jsonArray(filter.Loaders))
+	}
+	if len(filter.GameVersions) > 0 {
+		query.Set("game_versions", jsonArray(filter.GameVersions))
+	}
+	if filter.Featured != nil {
+		query.Set("featured", strconv.FormatBool(*filter.Featured))
+	}
+	return query
+}
+
+// GetProjectVersions lists the versions of a project, newest first. filter may be nil.
+func (c *Client) GetProjectVersions(ctx context.Context, idOrSlug string, filter *VersionFilter) ([]Version, error) {
+	var versions []Version
+	if err := c.get(ctx, "/project/"+url.PathEscape(idOrSlug)+"/version", filter.query(), &versions); err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
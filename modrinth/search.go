@@ -0,0 +1,57 @@
+package modrinth
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strconv"
+)
+
+// Sort orders for search results
+const (
+	IndexRelevance = "relevance"
+	IndexDownloads = "downloads"
+	IndexFollows   = "follows"
+	IndexNewest    = "newest"
+	IndexUpdated   = "updated"
+)
+
+// SearchParams describes a search. Facets are ANDed between the outer slices and ORed inside them,
+// like [][]string{{"categories:fabric"}, {"versions:1.21", "versions:1.21.1"}}.
+type SearchParams struct {
+	Query  string
+	Facets [][]string
+	Index  string
+	Offset int
+	Limit  int
+}
+
+// Search searches projects
+func (c *Client) Search(ctx context.Context, params SearchParams) (*SearchResult, error) {
+	query := url.Values{}
+	if params.Query != "" {
+		query.Set("query", params.Query)
+	}
+	if len(params.Facets) > 0 {
+		facets, err := json.Marshal(params.Facets)
+		if err != nil {
+			return nil, err
+		}
+		query.Set("facets", string(facets))
+	}
+	if params.Index != "" {
+		query.Set("index", params.Index)
+	}
+	if params.Offset > 0 {
+		query.Set("offset", strconv.Itoa(params.Offset))
+	}
+	if params.Limit > 0 {
+		query.Set("limit", strconv.Itoa(params.Limit))
+	}
+
+	var result SearchResult
+	if err := c.get(ctx, "/search", query, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
@@ -0,0 +1,30 @@
+package modrinth
+
+import "context"
+
+// GameVersions lists every Minecraft version known to Modrinth, newest first
+func (c *Client) GameVersions(ctx context.Context) ([]GameVersionTag, error) {
+	var tags []GameVersionTag
+	if err := c.get(ctx, "/tag/game_version", nil, &tags); err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// Loaders lists every loader known to Modrinth
+func (c *Client) Loaders(ctx context.Context) ([]LoaderTag, error) {
+	var tags []LoaderTag
+	if err := c.get(ctx, "/tag/loader", nil, &tags); err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// Categories lists every category known to Modrinth
+func (c *Client) Categories(ctx context.Context) ([]CategoryTag, error) {
+	var tags []CategoryTag
+	if err := c.get(ctx, "/tag/category", nil, &tags); err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
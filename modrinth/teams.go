@@ -0,0 +1,24 @@
+package modrinth
+
+import (
+	"context"
+	"net/url"
+)
+
+// GetProjectMembers lists the team members of a project
+func (c *Client) GetProjectMembers(ctx context.Context, idOrSlug string) ([]TeamMember, error) {
+	var members []TeamMember
+	if err := c.get(ctx, "/project/"+url.PathEscape(idOrSlug)+"/members", nil, &members); err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+// GetTeamMembers lists the members of a team
+func (c *Client) GetTeamMembers(ctx context.Context, teamID string) ([]TeamMember, error) {
+	var members []TeamMember
+	if err := c.get(ctx, "/team/"+url.PathEscape(teamID)+"/members", nil, &members); err != nil {
+		return nil, err
+	}
+	return members, nil
+}
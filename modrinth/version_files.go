@@ -0,0 +1,69 @@
+package modrinth
+
+import (
+	"context"
+	"net/url"
+)
+
+// Hash algorithms accepted by the version file endpoints
+const (
+	SHA1   = "sha1"
+	SHA512 = "sha512"
+)
+
+// UpdateFilter limits which versions count as an update, empty fields don't filter
+type UpdateFilter struct {
+	Loaders      []string `json:"loaders,omitempty"`
+	GameVersions []string `json:"game_versions,omitempty"`
+	VersionTypes []string `json:"version_types,omitempty"`
+}
+
+// VersionFromHash gets the version a file belongs to
+func (c *Client) VersionFromHash(ctx context.Context, hash string, algorithm string) (*Version, error) {
+	var version Version
+	query := url.Values{"algorithm": {algorithm}}
+	if err := c.get(ctx, "/version_file/"+url.PathEscape(hash), query, &version); err != nil {
+		return nil, err
+	}
+	return &version, nil
+}
+
+// VersionsFromHashes gets the versions of several files, keyed by hash. Unknown files are left out.
+func (c *Client) VersionsFromHashes(ctx context.Context, hashes []string, algorithm string) (map[string]Version, error) {
+	request := struct {
+		Hashes    []string `json:"hashes"`
+		Algorithm string   `json:"algorithm"`
+	}{hashes, algorithm}
+
+	versions := map[string]Version{}
+	if err := c.post(ctx, "/version_files", nil, request, &versions); err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+// LatestVersionFromHash gets the newest version of the project a file belongs to
+func (c *Client) LatestVersionFromHash(ctx context.Context, hash string, algorithm string, filter UpdateFilter) (*Version, error) {
+	var version Version
+	query := url.Values{"algorithm": {algorithm}}
+	if err := c.post(ctx, "/version_file/"+url.PathEscape(hash)+"/update", query, filter, &version); err != nil {
+		return nil, err
+	}
+	return &version, nil
+}
+
+// LatestVersionsFromHashes gets the newest version for several files, keyed by hash.
+// Files without a matching version are left out.
+func (c *Client) LatestVersionsFromHashes(ctx context.Context, hashes []string, algorithm string, filter UpdateFilter) (map[string]Version, error) {
+	request := struct {
+		Hashes    []string `json:"hashes"`
+		Algorithm string   `json:"algorithm"`
+		UpdateFilter
+	}{hashes, algorithm, filter}
+
+	versions := map[string]Version{}
+	if err := c.post(ctx, "/version_files/update", nil, request, &versions); err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
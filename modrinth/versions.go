@@ -0,0 +1,35 @@
+package modrinth
+
+import (
+	"context"
+	"net/url"
+)
+
+// GetVersion gets a version by its id
+func (c *Client) GetVersion(ctx context.Context, id string) (*Version, error) {
+	var version Version
+	if err := c.get(ctx, "/version/"+url.PathEscape(id), nil, &version); err != nil {
+		return nil, err
+	}
+	return &version, nil
+}
+
+// GetVersions gets several versions at once, unknown ids are left out of the result
+func (c *Client) GetVersions(ctx context.Context, ids []string) ([]Version, error) {
+	var versions []Version
+	query := url.Values{"ids": {jsonArray(ids)}}
+	if err := c.get(ctx, "/versions", query, &versions); err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+// GetProjectVersion gets a version of a project by its id or version number
+func (c *Client) GetProjectVersion(ctx context.Context, projectIDOrSlug string, idOrNumber string) (*Version, error) {
+	var version Version
+	path := "/project/" + url.PathEscape(projectIDOrSlug) + "/version/" + url.PathEscape(idOrNumber)
+	if err := c.get(ctx, path, nil, &version); err != nil {
+		return nil, err
+	}
+	return &version, nil
+}